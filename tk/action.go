@@ -4,16 +4,32 @@ package tk
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Action struct {
-	actid   string
-	label   string
-	checkid string
-	groupid string
-	radioid string
-	command *Command
-	data    interface{}
+	actid     string
+	label     string
+	underline int
+	checkid   string
+	groupid   string
+	radioid   string
+	command   *Command
+	data      interface{}
+	enabled   bool
+	visible   bool
+	icon      *Image
+	shortcut  Shortcut
+	tooltip   string
+	views     []actionView
+	changed   Command
+}
+
+// menu entry, toolbar button or shortcut created from action,
+// updated when action properties changed
+type actionView interface {
+	isValid() bool
+	updateAction(act *Action)
 }
 
 func (a *Action) String() string {
@@ -63,6 +79,182 @@ func (a *Action) Label() string {
 	return a.label
 }
 
+// underline index of mnemonic set by SetMnemonicLabel, -1 for no mnemonic
+func (a *Action) Underline() int {
+	return a.underline
+}
+
+// split "&File" to "File" and underline 0, "&&" is literal "&",
+// "&" followed by space or at end is literal too
+func splitMnemonic(label string) (string, int) {
+	if !strings.Contains(label, "&") {
+		return label, -1
+	}
+	runes := []rune(label)
+	var text []rune
+	underline := -1
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '&' && i+1 < len(runes) {
+			next := runes[i+1]
+			if next == '&' {
+				text = append(text, '&')
+				i++
+				continue
+			}
+			if next != ' ' {
+				if underline == -1 {
+					underline = len(text)
+				}
+				continue
+			}
+		}
+		text = append(text, r)
+	}
+	return string(text), underline
+}
+
+// set label text, "&" is literal and mnemonic is removed
+func (a *Action) SetLabel(label string) {
+	a.setLabel(label, -1)
+}
+
+// set label with mnemonic like "&Open" for underline of "O",
+// "&&" is literal "&"
+func (a *Action) SetMnemonicLabel(label string) {
+	a.setLabel(splitMnemonic(label))
+}
+
+func (a *Action) setLabel(label string, underline int) {
+	if a.label == label && a.underline == underline {
+		return
+	}
+	a.label = label
+	a.underline = underline
+	a.notify()
+}
+
+func (a *Action) SetEnabled(enabled bool) {
+	if a.enabled == enabled {
+		return
+	}
+	a.enabled = enabled
+	a.notify()
+}
+
+func (a *Action) IsEnabled() bool {
+	return a.enabled
+}
+
+// hidden action is removed from menus and toolbars
+func (a *Action) SetVisible(visible bool) {
+	if a.visible == visible {
+		return
+	}
+	a.visible = visible
+	a.notify()
+}
+
+func (a *Action) IsVisible() bool {
+	return a.visible
+}
+
+// set icon, nil for no icon
+func (a *Action) SetIcon(icon *Image) {
+	if a.icon == icon {
+		return
+	}
+	a.icon = icon
+	a.notify()
+}
+
+func (a *Action) Icon() *Image {
+	return a.icon
+}
+
+// set shortcut, menus show shortcut as accelerator text,
+// shortcut maps with the action bind the new key sequence,
+// returns ErrExist and keeps old shortcut if it is used in these maps
+func (a *Action) SetShortcut(shortcut Shortcut) error {
+	if a.shortcut == shortcut {
+		return nil
+	}
+	for _, v := range a.views {
+		if view, ok := v.(*shortcutActionView); ok && view.isValid() && view.isConflict(shortcut) {
+			return ErrExist
+		}
+	}
+	a.shortcut = shortcut
+	a.notify()
+	return nil
+}
+
+// parse and set shortcut, empty text for no shortcut
+func (a *Action) SetShortcutText(text string) error {
+	if text == "" {
+		return a.SetShortcut(Shortcut{})
+	}
+	sc, err := ParseShortcut(text)
+	if err != nil {
+		return err
+	}
+	return a.SetShortcut(sc)
+}
+
+func (a *Action) Shortcut() Shortcut {
+	return a.shortcut
+}
+
+func (a *Action) SetTooltip(tooltip string) {
+	if a.tooltip == tooltip {
+		return
+	}
+	a.tooltip = tooltip
+	a.notify()
+}
+
+func (a *Action) Tooltip() string {
+	return a.tooltip
+}
+
+// bind function called when action properties changed
+func (a *Action) OnChanged(fn func()) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	a.changed.Bind(fn)
+	return nil
+}
+
+func (a *Action) addView(v actionView) {
+	a.views = append(a.views, v)
+}
+
+func (a *Action) removeView(v actionView) {
+	for n, view := range a.views {
+		if view == v {
+			a.views = append(a.views[:n], a.views[n+1:]...)
+			return
+		}
+	}
+}
+
+// update valid views, views of destroyed widgets are removed
+func (a *Action) notify() {
+	views := a.views[:0]
+	for _, v := range a.views {
+		if v.isValid() {
+			views = append(views, v)
+		}
+	}
+	a.views = views
+	// views may be changed by update
+	for _, v := range append([]actionView(nil), views...) {
+		v.updateAction(a)
+	}
+	a.changed.Invoke()
+}
+
 func (a *Action) SetData(data interface{}) {
 	a.data = data
 }
@@ -75,6 +267,20 @@ func (a *Action) Invoke() {
 	a.command.Invoke()
 }
 
+// toggle check action or check radio action and invoke command like menu entry,
+// disabled action is ignored
+func (a *Action) Trigger() {
+	if !a.enabled {
+		return
+	}
+	if a.IsRadioAction() {
+		a.SetChecked(true)
+	} else if a.IsCheckAction() {
+		a.SetChecked(!a.IsChecked())
+	}
+	a.command.Invoke()
+}
+
 func (a *Action) OnCommand(fn func()) error {
 	if fn == nil {
 		return ErrInvalid
@@ -84,7 +290,7 @@ func (a *Action) OnCommand(fn func()) error {
 }
 
 func NewAction(label string) *Action {
-	act := &Action{}
+	act := &Action{enabled: true, visible: true, underline: -1}
 	act.label = label
 	act.actid = makeActionId()
 	act.command = &Command{}
@@ -119,7 +325,7 @@ func NewRadioAction(group *ActionGroup, label string) *Action {
 }
 
 func NewSeparatorAction() *Action {
-	action := &Action{}
+	action := &Action{enabled: true, visible: true, underline: -1}
 	return action
 }
 
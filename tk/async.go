@@ -0,0 +1,69 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/visualfc/atk/tk/interp"
+)
+
+// run fn on main thread and wait for it to finish, run fn directly on main thread.
+// returns ErrClosed if main loop is not running. fn is not run if ctx error is returned,
+// if ctx is done after fn starts, AsyncWait waits for fn and returns nil.
+func AsyncWait(ctx context.Context, fn func()) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	if interp.IsMainThread() {
+		fn()
+		return nil
+	}
+	if !interp.IsMainLoopRunning() {
+		return ErrClosed
+	}
+	// the posted func and the cancel branch claim to decide the single outcome
+	var claimed atomic.Bool
+	done := make(chan error, 1)
+	posted := interp.Async(func() {
+		if !claimed.CompareAndSwap(false, true) {
+			return
+		}
+		err := ctx.Err()
+		if err == nil {
+			fn()
+		}
+		done <- err
+	})
+	if !posted {
+		return ErrClosed
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if claimed.CompareAndSwap(false, true) {
+			return ctx.Err()
+		}
+		return <-done
+	}
+}
+
+// run fn on main thread and return the result, run fn directly on main thread.
+// returns ErrClosed if main loop is not running, see AsyncWait.
+func Call[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var r T
+	var err error
+	if fn == nil {
+		return r, ErrInvalid
+	}
+	werr := AsyncWait(ctx, func() {
+		r, err = fn()
+	})
+	if werr != nil {
+		var zero T
+		return zero, werr
+	}
+	return r, err
+}
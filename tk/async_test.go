@@ -0,0 +1,60 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	registerTest("Async", testAsync)
+}
+
+func testAsync(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+	w.SetText("hello")
+
+	// inline on main thread
+	if v, err := Call(context.Background(), func() (string, error) {
+		return w.PlainText(), nil
+	}); err != nil || v != "hello\n" {
+		t.Fatal("Call", v, err)
+	}
+
+	ch := make(chan string, 1)
+	go func() {
+		v, _ := Call(context.Background(), func() (string, error) {
+			return w.Get(TextIndexStart, "1.end"), nil
+		})
+		ch <- v
+	}()
+	var v string
+	for done := false; !done; {
+		Update()
+		select {
+		case v = <-ch:
+			done = true
+		default:
+		}
+	}
+	if v != "hello" {
+		t.Fatal("Call", "hello", v)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	errc := make(chan error, 1)
+	go func() {
+		errc <- AsyncWait(ctx, func() { called = true })
+	}()
+	if err := <-errc; err != context.Canceled {
+		t.Fatal("AsyncWait", context.Canceled, err)
+	}
+	Update()
+	if called {
+		t.Fatal("AsyncWait called after cancel")
+	}
+}
@@ -15,7 +15,7 @@ type BaseWidget struct {
 }
 
 func (w *BaseWidget) String() string {
-	iw := globalWidgetMap[w.id]
+	iw := FindWidget(w.id)
 	if iw != nil {
 		return fmt.Sprintf("%v{%v}", iw.TypeName(), w.id)
 	} else {
@@ -136,15 +136,30 @@ func (w *BaseWidget) SetAttributes(attributes ...*WidgetAttr) error {
 	return nil
 }
 
+// add bind event, use OnEvent for binding handle to unbind
 func (w *BaseWidget) BindEvent(event string, fn func(e *Event)) error {
-	return BindEvent(w.id, event, fn)
+	_, err := w.OnEvent(event, fn)
+	return err
 }
 
+// add bind key press event, use OnKey for binding handle to unbind
 func (w *BaseWidget) BindKeyEvent(fn func(e *KeyEvent)) error {
-	return BindKeyEventEx(w.id, fn, nil)
+	_, err := w.OnKey(fn, nil)
+	return err
 }
 
 func (w *BaseWidget) BindKeyEventEx(fnPress func(e *KeyEvent), fnRelease func(e *KeyEvent)) error {
+	_, err := w.OnKey(fnPress, fnRelease)
+	return err
+}
+
+// add bind event, returns binding handle for unbind
+func (w *BaseWidget) OnEvent(event string, fn func(e *Event)) (*Binding, error) {
+	return BindEvent(w.id, event, fn)
+}
+
+// add bind key press and release events, returns binding handle for unbind
+func (w *BaseWidget) OnKey(fnPress func(e *KeyEvent), fnRelease func(e *KeyEvent)) (*Binding, error) {
 	return BindKeyEventEx(w.id, fnPress, fnRelease)
 }
 
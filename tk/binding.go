@@ -0,0 +1,213 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// handle of event handlers added by BindEvent, BindKeyEventEx, canvas item or text tag bind
+type Binding struct {
+	tag    string
+	owner  string // widget id of canvas or text for item and tag bind
+	cmd    string // bind command of item and tag bind
+	events []string
+	fnids  []string
+}
+
+func newItemBinding(owner string, cmd string, tag string) *Binding {
+	return &Binding{tag: tag, owner: owner, cmd: fmt.Sprintf("%v %v %v", owner, cmd, Quote(tag))}
+}
+
+func (b *Binding) bindCmd() string {
+	if b.cmd != "" {
+		return b.cmd
+	}
+	return "bind " + b.tag
+}
+
+func (b *Binding) ownerId() string {
+	if b.owner != "" {
+		return b.owner
+	}
+	return b.tag
+}
+
+func (b *Binding) Tag() string {
+	return b.tag
+}
+
+func (b *Binding) Events() []string {
+	return b.events
+}
+
+func (b *Binding) IsValid() bool {
+	return len(b.fnids) > 0
+}
+
+// remove the handlers of binding and delete their tcl commands,
+// other handlers of the same tag and event are kept. commands of destroyed
+// widget are already deleted, so unbind is no-op and returns nil
+func (b *Binding) Unbind() error {
+	if !b.IsValid() {
+		return ErrInvalid
+	}
+	if id := b.ownerId(); strings.HasPrefix(id, ".") {
+		if ok, _ := evalAsBool(fmt.Sprintf("winfo exists %v", id)); !ok {
+			b.fnids = nil
+			return nil
+		}
+	}
+	var err error
+	for n, fnid := range b.fnids {
+		e := eval(fmt.Sprintf("%v %v [join [lsearch -all -inline -not -glob [split [%v %v] \\n] {%v *}] \\n]",
+			b.bindCmd(), b.events[n], b.bindCmd(), b.events[n], fnid))
+		if e != nil && err == nil {
+			err = e
+		}
+		removeWidgetAction(b.ownerId(), fnid)
+		eval(fmt.Sprintf("catch {rename %v {}}", fnid))
+	}
+	b.fnids = nil
+	return err
+}
+
+func (b *Binding) add(event string, fn func(e *Event)) error {
+	var ev Event
+	return b.addAction(event, ev.params(), func(args []string) {
+		ev.parser(args)
+		fn(&ev)
+	})
+}
+
+// add handler for event with substitution params
+func (b *Binding) addAction(event string, params string, fn func(args []string)) error {
+	fnid := makeBindEventId()
+	mainInterp.CreateAction(fnid, fn)
+	err := eval(fmt.Sprintf("%v %v {+%v %v}", b.bindCmd(), event, fnid, params))
+	if err != nil {
+		eval(fmt.Sprintf("catch {rename %v {}}", fnid))
+		return err
+	}
+	addWidgetAction(b.ownerId(), fnid)
+	b.events = append(b.events, event)
+	b.fnids = append(b.fnids, fnid)
+	return nil
+}
+
+// add bind event, returns binding handle for unbind
+func BindEvent(tag string, event string, fn func(e *Event)) (*Binding, error) {
+	if tag == "" || !IsEvent(event) || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	err := b.add(event, fn)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// add bind key press and release events, returns binding handle for unbind,
+// key modifier is from event state and pressed modifier key
+func BindKeyEventEx(tag string, fnPress func(e *KeyEvent), fnRelease func(e *KeyEvent)) (*Binding, error) {
+	if tag == "" {
+		return nil, ErrInvalid
+	}
+	var ke KeyEvent
+	b := &Binding{tag: tag}
+	err := b.add("<KeyPress>", func(e *Event) {
+		ke.Event = e
+		ke.KeyModifier = parserEventState(e.State).KeyModifier() | keysymModifier(e.KeySym)
+		if fnPress != nil {
+			fnPress(&ke)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = b.add("<KeyRelease>", func(e *Event) {
+		ke.Event = e
+		ke.KeyModifier = parserEventState(e.State).KeyModifier() &^ keysymModifier(e.KeySym)
+		if fnRelease != nil {
+			fnRelease(&ke)
+		}
+	})
+	if err != nil {
+		b.Unbind()
+		return nil, err
+	}
+	return b, nil
+}
+
+// tcl commands owned by widgets, deleted when the widget is destroyed
+var (
+	globalWidgetActions     = make(map[string][]string)
+	globalWidgetActionsLock sync.Mutex
+)
+
+// bindtag for widget destroy notify
+const widgetDestroyTag = "atk_destroy"
+
+func init() {
+	registerInit(func() {
+		mainInterp.CreateAction("atk_widget_destroy", func(args []string) {
+			if len(args) == 1 {
+				removeWidget(args[0])
+			}
+		})
+		eval(fmt.Sprintf("bind %v <Destroy> {atk_widget_destroy %%W}", widgetDestroyTag))
+	})
+}
+
+// add tcl command to widget id, ignored if id is not widget
+func addWidgetAction(id string, act string) {
+	if _, ok := LookupWidget(id); !ok {
+		return
+	}
+	globalWidgetActionsLock.Lock()
+	list, ok := globalWidgetActions[id]
+	globalWidgetActions[id] = append(list, act)
+	globalWidgetActionsLock.Unlock()
+	if !ok {
+		eval(fmt.Sprintf("if {[lsearch -exact [bindtags %v] %v] == -1} {bindtags %v [linsert [bindtags %v] end %v]}",
+			id, widgetDestroyTag, id, id, widgetDestroyTag))
+	}
+}
+
+func removeWidgetAction(id string, act string) {
+	globalWidgetActionsLock.Lock()
+	list := globalWidgetActions[id]
+	for n, v := range list {
+		if v == act {
+			globalWidgetActions[id] = append(list[:n], list[n+1:]...)
+			break
+		}
+	}
+	globalWidgetActionsLock.Unlock()
+}
+
+// delete tcl commands of widget id and children
+func releaseWidgetActions(id string) {
+	var acts []string
+	globalWidgetActionsLock.Lock()
+	for k, list := range globalWidgetActions {
+		if id == "." || k == id || strings.HasPrefix(k, id+".") {
+			acts = append(acts, list...)
+			delete(globalWidgetActions, k)
+		}
+	}
+	globalWidgetActionsLock.Unlock()
+	for _, act := range acts {
+		eval(fmt.Sprintf("catch {rename %v {}}", act))
+	}
+}
+
+// number of tcl commands owned by widget id
+func widgetActionCount(id string) int {
+	globalWidgetActionsLock.Lock()
+	defer globalWidgetActionsLock.Unlock()
+	return len(globalWidgetActions[id])
+}
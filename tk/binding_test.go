@@ -0,0 +1,78 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	registerTest("Binding", testBinding)
+}
+
+func testBinding(t *testing.T) {
+	w := NewFrame(nil)
+	var n1, n2 int
+	b1, err := w.OnEvent("<<AtkTest>>", func(e *Event) {
+		n1++
+	})
+	if err != nil {
+		t.Fatal("OnEvent", err)
+	}
+	_, err = BindEvent(w.Id(), "<<AtkTest>>", func(e *Event) {
+		n2++
+	})
+	if err != nil {
+		t.Fatal("BindEvent", err)
+	}
+	SendEvent(w, "<<AtkTest>>")
+	if n1 != 1 || n2 != 1 {
+		t.Fatal("BindEvent", n1, n2)
+	}
+	fnid := b1.fnids[0]
+	if err := b1.Unbind(); err != nil {
+		t.Fatal("Unbind", err)
+	}
+	if b1.IsValid() {
+		t.Fatal("IsValid", b1)
+	}
+	SendEvent(w, "<<AtkTest>>")
+	if n1 != 1 || n2 != 2 {
+		t.Fatal("Unbind", n1, n2)
+	}
+	if v, _ := evalAsString(fmt.Sprintf("info commands %v", fnid)); v != "" {
+		t.Fatal("Unbind command", v)
+	}
+
+	bk, err := w.OnKey(nil, nil)
+	if err != nil || len(bk.Events()) != 2 {
+		t.Fatal("OnKey", bk, err)
+	}
+	if v := widgetActionCount(w.Id()); v != 3 {
+		t.Fatal("widgetActionCount", 3, v)
+	}
+	fnids := append([]string{}, bk.fnids...)
+	w.Destroy()
+	if v := widgetActionCount(w.Id()); v != 0 {
+		t.Fatal("widgetActionCount", 0, v)
+	}
+	for _, fnid := range fnids {
+		if v, _ := evalAsString(fmt.Sprintf("info commands %v", fnid)); v != "" {
+			t.Fatal("Destroy command", v)
+		}
+	}
+
+	// destroy by tcl
+	w2 := NewFrame(nil)
+	child := NewFrame(w2)
+	cb, _ := BindEvent(child.Id(), "<<AtkTest>>", func(e *Event) {})
+	eval(fmt.Sprintf("destroy %v", w2.Id()))
+	if child.IsValid() || widgetActionCount(child.Id()) != 0 {
+		t.Fatal("destroy", child.IsValid())
+	}
+	if err := cb.Unbind(); err != nil || cb.IsValid() {
+		t.Fatal("Unbind destroyed", err)
+	}
+	w2.Destroy()
+}
@@ -2,7 +2,11 @@
 
 package tk
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Create and manipulate 'canvas' hypergraphics drawing surface widgets
 type Canvas struct {
@@ -240,6 +244,120 @@ func (w *Canvas) YScrollIncrement() int {
 	return r
 }
 
+func (w *Canvas) SetScrollRegionN(x1 float64, y1 float64, x2 float64, y2 float64) error {
+	return eval(fmt.Sprintf("%v configure -scrollregion {%v %v %v %v}", w.id, x1, y1, x2, y2))
+}
+
+func (w *Canvas) ScrollRegionN() (x1 float64, y1 float64, x2 float64, y2 float64) {
+	r, err := evalAsStringList(fmt.Sprintf("%v cget -scrollregion", w.id))
+	if err != nil || len(r) != 4 {
+		return
+	}
+	var ar []*float64 = []*float64{&x1, &y1, &x2, &y2}
+	for n, s := range r {
+		*ar[n], _ = strconv.ParseFloat(s, 64)
+	}
+	return
+}
+
+func (w *Canvas) ClearScrollRegion() error {
+	return eval(fmt.Sprintf("%v configure -scrollregion {}", w.id))
+}
+
+// canvas x-coordinate of the window x-coordinate
+func (w *Canvas) CanvasX(x int) float64 {
+	r, _ := evalAsFloat64(fmt.Sprintf("%v canvasx %v", w.id, x))
+	return r
+}
+
+// canvas y-coordinate of the window y-coordinate
+func (w *Canvas) CanvasY(y int) float64 {
+	r, _ := evalAsFloat64(fmt.Sprintf("%v canvasy %v", w.id, y))
+	return r
+}
+
+func (w *Canvas) XViewMoveTo(fraction float64) error {
+	return eval(fmt.Sprintf("%v xview moveto %v", w.id, fraction))
+}
+
+func (w *Canvas) YViewMoveTo(fraction float64) error {
+	return eval(fmt.Sprintf("%v yview moveto %v", w.id, fraction))
+}
+
+func (w *Canvas) ScanMark(x int, y int) error {
+	return eval(fmt.Sprintf("%v scan mark %v %v", w.id, x, y))
+}
+
+func (w *Canvas) ScanDragTo(x int, y int, gain int) error {
+	return eval(fmt.Sprintf("%v scan dragto %v %v %v", w.id, x, y, gain))
+}
+
+func (w *Canvas) SetXViewArgs(args []string) error {
+	return eval(fmt.Sprintf("%v xview %v", w.id, strings.Join(args, " ")))
+}
+
+func (w *Canvas) SetYViewArgs(args []string) error {
+	return eval(fmt.Sprintf("%v yview %v", w.id, strings.Join(args, " ")))
+}
+
+func (w *Canvas) OnXScrollEx(fn func([]string) error) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	if w.xscrollcommand == nil {
+		w.xscrollcommand = &CommandEx{}
+		bindCommandEx(w.id, "xscrollcommand", w.xscrollcommand.Invoke)
+	}
+	w.xscrollcommand.Bind(fn)
+	return nil
+}
+
+func (w *Canvas) OnYScrollEx(fn func([]string) error) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	if w.yscrollcommand == nil {
+		w.yscrollcommand = &CommandEx{}
+		bindCommandEx(w.id, "yscrollcommand", w.yscrollcommand.Invoke)
+	}
+	w.yscrollcommand.Bind(fn)
+	return nil
+}
+
+func (w *Canvas) BindXScrollBar(bar *ScrollBar) error {
+	if !IsValidWidget(bar) {
+		return ErrInvalid
+	}
+	w.OnXScrollEx(bar.SetScrollArgs)
+	bar.OnCommandEx(w.SetXViewArgs)
+	return nil
+}
+
+func (w *Canvas) BindYScrollBar(bar *ScrollBar) error {
+	if !IsValidWidget(bar) {
+		return ErrInvalid
+	}
+	w.OnYScrollEx(bar.SetScrollArgs)
+	bar.OnCommandEx(w.SetYViewArgs)
+	return nil
+}
+
+type CanvasEx struct {
+	*ScrollLayout
+	*Canvas
+}
+
+func NewCanvasEx(parent Widget, attributs ...*WidgetAttr) *CanvasEx {
+	w := &CanvasEx{}
+	w.ScrollLayout = NewScrollLayout(parent)
+	w.Canvas = NewCanvas(parent, attributs...)
+	w.SetWidget(w.Canvas)
+	w.Canvas.BindXScrollBar(w.XScrollBar)
+	w.Canvas.BindYScrollBar(w.YScrollBar)
+	RegisterWidget(w)
+	return w
+}
+
 func CanvasAttrBackground(color string) *WidgetAttr {
 	return &WidgetAttr{"background", color}
 }
@@ -327,3 +445,7 @@ func CanvasAttrXScrollIncrement(value int) *WidgetAttr {
 func CanvasAttrYScrollIncrement(value int) *WidgetAttr {
 	return &WidgetAttr{"yscrollincrement", value}
 }
+
+func CanvasAttrScrollRegion(x1 float64, y1 float64, x2 float64, y2 float64) *WidgetAttr {
+	return &WidgetAttr{"scrollregion", fmt.Sprintf("%v %v %v %v", x1, y1, x2, y2)}
+}
@@ -2,10 +2,20 @@
 
 package tk
 
-import "testing"
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+)
 
 func init() {
 	registerTest("Canvas", testCanvas)
+	registerTest("CanvasItem", testCanvasItem)
+	registerTest("CanvasItemTag", testCanvasItemTag)
+	registerTest("CanvasViewport", testCanvasViewport)
+	registerTest("CanvasExport", testCanvasExport)
 }
 
 func testCanvas(t *testing.T) {
@@ -122,3 +132,201 @@ func testCanvas(t *testing.T) {
 		t.Fatal("YScrollIncrement", 20, v)
 	}
 }
+
+func testCanvasItem(t *testing.T) {
+	w := NewCanvas(nil, CanvasAttrWidth(200), CanvasAttrHeight(200))
+	defer w.Destroy()
+
+	line := w.CreateLine([]float64{10, 10, 50, 50}, CanvasItemAttrFill("red"), CanvasItemAttrWidth(2), CanvasItemAttrArrow(ArrowStyleLast))
+	if line == nil {
+		t.Fatal("CreateLine")
+	}
+	if v := line.Type(); v != CanvasItemTypeLine {
+		t.Fatal("Type", CanvasItemTypeLine, v)
+	}
+	if v := line.NativeAttribute("fill"); v != "red" {
+		t.Fatal("NativeAttribute", "red", v)
+	}
+
+	rect := w.CreateRectangle(20, 20, 60, 80, CanvasItemAttrOutline("blue"))
+	if v := rect.Coords(); len(v) != 4 || v[0] != 20 || v[3] != 80 {
+		t.Fatal("Coords", v)
+	}
+	rect.Move(10, 5)
+	if v := rect.Coords(); v[0] != 30 || v[1] != 25 {
+		t.Fatal("Move", v)
+	}
+	rect.SetCoords(0, 0, 100, 100)
+	rect.Scale(0, 0, 0.5, 0.5)
+	if v := rect.Coords(); v[2] != 50 || v[3] != 50 {
+		t.Fatal("Scale", v)
+	}
+	rect.Configure(CanvasItemAttrFill("green"))
+	if v := rect.NativeAttribute("fill"); v != "green" {
+		t.Fatal("Configure", "green", v)
+	}
+
+	text := w.CreateText(10, 10, "hello {world}", CanvasItemAttrAnchor(AnchorNorthWest))
+	if v := text.NativeAttribute("text"); v != "hello {world}" {
+		t.Fatal("CreateText", v)
+	}
+	w.CreateOval(0, 0, 10, 10)
+	w.CreateArc(0, 0, 10, 10, CanvasItemAttrArcStart(0), CanvasItemAttrArcExtent(90), CanvasItemAttrArcStyle(ArcStyleChord))
+	w.CreatePolygon([]float64{0, 0, 10, 0, 5, 10})
+	if v := w.ItemCount(); v != 6 {
+		t.Fatal("ItemCount", 6, v)
+	}
+
+	line.Raise(nil)
+	if items := w.Items(); items[len(items)-1].Id() != line.Id() {
+		t.Fatal("Raise", items)
+	}
+	line.Lower(nil)
+	if items := w.Items(); items[0].Id() != line.Id() {
+		t.Fatal("Lower", items)
+	}
+
+	if g := rect.BBox(); g.Width <= 0 || g.Height <= 0 {
+		t.Fatal("BBox", g)
+	}
+
+	line.Delete()
+	if line.IsValid() {
+		t.Fatal("Delete")
+	}
+	w.DeleteAllItems()
+	if v := w.ItemCount(); v != 0 {
+		t.Fatal("DeleteAllItems", v)
+	}
+}
+
+func testCanvasItemTag(t *testing.T) {
+	w := NewCanvas(nil, CanvasAttrWidth(200), CanvasAttrHeight(200))
+	defer w.Destroy()
+
+	r1 := w.CreateRectangle(0, 0, 10, 10, CanvasItemAttrTags([]string{"shape"}))
+	r2 := w.CreateRectangle(50, 50, 60, 60)
+	r2.AddTag("shape", "selected")
+	if v := r2.Tags(); len(v) != 2 || v[0] != "shape" || v[1] != "selected" {
+		t.Fatal("Tags", v)
+	}
+	if v := w.FindWithTag("shape"); len(v) != 2 {
+		t.Fatal("FindWithTag", v)
+	}
+	r2.RemoveTag("selected")
+	if r2.HasTag("selected") {
+		t.Fatal("RemoveTag")
+	}
+	if v := w.FindOverlapping(5, 5, 6, 6); len(v) != 1 || v[0].Id() != r1.Id() {
+		t.Fatal("FindOverlapping", v)
+	}
+	if v := w.FindEnclosed(40, 40, 70, 70); len(v) != 1 || v[0].Id() != r2.Id() {
+		t.Fatal("FindEnclosed", v)
+	}
+	if v := w.FindClosest(48, 48, 0); v == nil || v.Id() != r2.Id() {
+		t.Fatal("FindClosest", v)
+	}
+	w.RemoveTag("shape")
+	if v := w.FindWithTag("shape"); len(v) != 0 {
+		t.Fatal("RemoveTag", v)
+	}
+
+	ib, err := r1.BindEvent("<ButtonPress-1>", func(e *Event) {})
+	if err != nil {
+		t.Fatal("BindEvent", err)
+	}
+	if v := r1.BindInfo(); len(v) != 1 {
+		t.Fatal("BindInfo", v)
+	}
+	if err := ib.Unbind(); err != nil || len(r1.BindInfo()) != 0 {
+		t.Fatal("Unbind", err, r1.BindInfo())
+	}
+	r1.ClearBind("<ButtonPress-1>")
+}
+
+func testCanvasViewport(t *testing.T) {
+	w := NewCanvas(nil, CanvasAttrWidth(200), CanvasAttrHeight(200))
+	defer w.Destroy()
+
+	w.SetScrollRegionN(0, 0, 400, 300)
+	if x1, y1, x2, y2 := w.ScrollRegionN(); x1 != 0 || y1 != 0 || x2 != 400 || y2 != 300 {
+		t.Fatal("ScrollRegion", x1, y1, x2, y2)
+	}
+
+	rect := w.CreateRectangle(10, 10, 20, 20)
+	v := NewCanvasViewport(w)
+	v.SetWorldRegionN(0, 0, 1000, 1000)
+	v.ZoomAt(2, 0, 0)
+	if z := v.Zoom(); z != 2 {
+		t.Fatal("Zoom", 2, z)
+	}
+	if c := rect.Coords(); c[0] != 20 || c[2] != 40 {
+		t.Fatal("ZoomAt", c)
+	}
+	if x1, y1, x2, y2 := w.ScrollRegionN(); x1 != 0 || y1 != 0 || x2 != 2000 || y2 != 2000 {
+		t.Fatal("ScrollRegion", x1, y1, x2, y2)
+	}
+	v.SetZoomRange(0.5, 4)
+	v.ZoomAt(100, 0, 0)
+	if z := v.Zoom(); z != 4 {
+		t.Fatal("ZoomRange", 4, z)
+	}
+	v.ResetZoom()
+	if c := rect.Coords(); c[0] != 10 || c[2] != 20 {
+		t.Fatal("ResetZoom", c)
+	}
+	v.ZoomAt(0.7, 0, 0)
+	if x, y := v.WorldToWindow(10, 10); x != 7 || y != 7 {
+		t.Fatal("WorldToWindow", 7, 7, x, y)
+	}
+	v.ResetZoom()
+	if err := v.EnableWheelZoom(1); err != ErrInvalid {
+		t.Fatal("EnableWheelZoom", ErrInvalid, err)
+	}
+	if err := v.EnableWheelZoom(1.2); err != nil {
+		t.Fatal("EnableWheelZoom", err)
+	}
+	// button 4 is bound only for x11 wheel before Tk 8.7
+	old, _ := evalAsInt("package vcompare $tk_version 8.7")
+	button4, _ := evalAsString(fmt.Sprintf("bind %v <ButtonPress-4>", w.Id()))
+	if (button4 != "") != (WindowingSystem() == "x11" && old < 0) {
+		t.Fatal("EnableWheelZoom button 4", button4)
+	}
+	v.EnableMiddleButtonPan()
+}
+
+func testCanvasExport(t *testing.T) {
+	w := NewCanvas(nil, CanvasAttrWidth(100), CanvasAttrHeight(100), CanvasAttrBackground("white"))
+	defer w.Destroy()
+
+	w.CreateRectangle(10, 10, 50, 50, CanvasItemAttrFill("red"), CanvasItemAttrOutline(""))
+	w.CreateLine([]float64{0, 80, 100, 80}, CanvasItemAttrFill("blue"), CanvasItemAttrWidth(3))
+	w.SetScrollRegionN(0, 0, 100, 100)
+
+	var buf bytes.Buffer
+	err := w.WritePostscript(&buf, PostscriptAttrColorMode(PostscriptColorModeGray), PostscriptAttrRotate(true), PostscriptAttrPageWidth("100m"))
+	if err != nil {
+		t.Fatal("WritePostscript", err)
+	}
+	if !strings.HasPrefix(buf.String(), "%!PS-Adobe") {
+		t.Fatal("WritePostscript", buf.String())
+	}
+
+	img := w.ToImage()
+	if img == nil || img.Bounds().Dx() != 100 || img.Bounds().Dy() != 100 {
+		t.Fatal("ToImage", img)
+	}
+	if c := color.RGBAModel.Convert(img.At(30, 30)).(color.RGBA); c.R != 0xff || c.G != 0 || c.B != 0 {
+		t.Fatal("ToImage fill", c)
+	}
+	if c := color.RGBAModel.Convert(img.At(70, 80)).(color.RGBA); c.B != 0xff || c.R != 0 {
+		t.Fatal("ToImage line", c)
+	}
+	if c := color.RGBAModel.Convert(img.At(70, 30)).(color.RGBA); c.R != 0xff || c.G != 0xff || c.B != 0xff {
+		t.Fatal("ToImage background", c)
+	}
+	buf.Reset()
+	if err := w.WritePNG(&buf); err != nil {
+		t.Fatal("WritePNG", err)
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PostscriptColorMode int
+
+const (
+	PostscriptColorModeColor PostscriptColorMode = iota
+	PostscriptColorModeGray
+	PostscriptColorModeMono
+)
+
+var (
+	postscriptColorModeName = []string{"color", "gray", "mono"}
+)
+
+func (v PostscriptColorMode) String() string {
+	if v >= 0 && int(v) < len(postscriptColorModeName) {
+		return postscriptColorModeName[v]
+	}
+	return ""
+}
+
+type PostscriptAttr struct {
+	key   string
+	value interface{}
+}
+
+// page width, screen distance like "210m" "8.5i" "600p"
+func PostscriptAttrPageWidth(width string) *PostscriptAttr {
+	return &PostscriptAttr{"pagewidth", width}
+}
+
+// page height, screen distance like "297m" "11i" "800p"
+func PostscriptAttrPageHeight(height string) *PostscriptAttr {
+	return &PostscriptAttr{"pageheight", height}
+}
+
+func PostscriptAttrPageAnchor(anchor Anchor) *PostscriptAttr {
+	return &PostscriptAttr{"pageanchor", anchor}
+}
+
+// page x-position of the anchor point, screen distance
+func PostscriptAttrPageX(x string) *PostscriptAttr {
+	return &PostscriptAttr{"pagex", x}
+}
+
+// page y-position of the anchor point, screen distance
+func PostscriptAttrPageY(y string) *PostscriptAttr {
+	return &PostscriptAttr{"pagey", y}
+}
+
+// rotate to landscape orientation
+func PostscriptAttrRotate(rotate bool) *PostscriptAttr {
+	return &PostscriptAttr{"rotate", boolToInt(rotate)}
+}
+
+func PostscriptAttrColorMode(mode PostscriptColorMode) *PostscriptAttr {
+	return &PostscriptAttr{"colormode", mode}
+}
+
+// left edge of canvas area to print, default is the visible area of the window
+func PostscriptAttrX(x float64) *PostscriptAttr {
+	return &PostscriptAttr{"x", x}
+}
+
+// top edge of canvas area to print
+func PostscriptAttrY(y float64) *PostscriptAttr {
+	return &PostscriptAttr{"y", y}
+}
+
+// width of canvas area to print
+func PostscriptAttrWidth(width float64) *PostscriptAttr {
+	return &PostscriptAttr{"width", width}
+}
+
+// height of canvas area to print
+func PostscriptAttrHeight(height float64) *PostscriptAttr {
+	return &PostscriptAttr{"height", height}
+}
+
+// generate encapsulated postscript of the canvas
+func (w *Canvas) Postscript(attributes ...*PostscriptAttr) (string, error) {
+	var attrList []string
+	for _, attr := range attributes {
+		if attr == nil {
+			continue
+		}
+		if s, ok := attr.value.(string); ok {
+			pname := "atk_tmp_" + attr.key
+			setObjText(pname, s)
+			attrList = append(attrList, fmt.Sprintf("-%v $%v", attr.key, pname))
+			continue
+		}
+		attrList = append(attrList, fmt.Sprintf("-%v {%v}", attr.key, attr.value))
+	}
+	script := fmt.Sprintf("%v postscript", w.id)
+	if len(attrList) > 0 {
+		script += " " + strings.Join(attrList, " ")
+	}
+	return evalAsString(script)
+}
+
+func (w *Canvas) WritePostscript(writer io.Writer, attributes ...*PostscriptAttr) error {
+	ps, err := w.Postscript(attributes...)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(writer, ps)
+	return err
+}
+
+// Render canvas items in scroll region (or bounding box of all items)
+// to image. Rendering is done in Go from the item list: line, rectangle,
+// oval, arc, polygon and image items are drawn, text, bitmap and window
+// items are skipped (use Postscript for text output), dash, smooth and
+// arrow options are ignored.
+func (w *Canvas) ToImage() image.Image {
+	x1, y1, x2, y2 := w.ScrollRegionN()
+	if x2 <= x1 || y2 <= y1 {
+		bx1, by1, bx2, by2 := w.BBoxN()
+		x1, y1, x2, y2 = float64(bx1), float64(by1), float64(bx2), float64(by2)
+	}
+	return w.ToImageRegion(x1, y1, x2, y2)
+}
+
+func (w *Canvas) ToImageRegion(x1 float64, y1 float64, x2 float64, y2 float64) image.Image {
+	width := int(math.Ceil(x2 - x1))
+	height := int(math.Ceil(y2 - y1))
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	r := &canvasRender{canvas: w, ox: x1, oy: y1, colors: make(map[string]color.Color)}
+	r.img = image.NewRGBA(image.Rect(0, 0, width, height))
+	if bg := r.color(w.Background()); bg != nil {
+		draw.Draw(r.img, r.img.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+	for _, item := range w.Items() {
+		r.drawItem(item)
+	}
+	return r.img
+}
+
+func (w *Canvas) WritePNG(writer io.Writer) error {
+	img := w.ToImage()
+	if img == nil {
+		return ErrInvalid
+	}
+	return png.Encode(writer, img)
+}
+
+type canvasPoint struct {
+	X float64
+	Y float64
+}
+
+type canvasRender struct {
+	canvas *Canvas
+	img    *image.RGBA
+	ox     float64
+	oy     float64
+	colors map[string]color.Color
+}
+
+func (r *canvasRender) color(name string) color.Color {
+	if name == "" {
+		return nil
+	}
+	if c, ok := r.colors[name]; ok {
+		return c
+	}
+	setObjText("atk_tmp_color", name)
+	rgb, err := evalAsIntList(fmt.Sprintf("winfo rgb %v $atk_tmp_color", r.canvas.id))
+	var c color.Color
+	if err == nil && len(rgb) == 3 {
+		c = color.RGBA64{uint16(rgb[0]), uint16(rgb[1]), uint16(rgb[2]), 0xffff}
+	}
+	r.colors[name] = c
+	return c
+}
+
+func (r *canvasRender) points(coords []float64) (pts []canvasPoint) {
+	for i := 0; i+1 < len(coords); i += 2 {
+		pts = append(pts, canvasPoint{coords[i] - r.ox, coords[i+1] - r.oy})
+	}
+	return
+}
+
+func (r *canvasRender) lineWidth(item *CanvasItem) float64 {
+	v, err := strconv.ParseFloat(item.NativeAttribute("width"), 64)
+	if err != nil || v <= 0 {
+		return 1
+	}
+	return v
+}
+
+func (r *canvasRender) drawItem(item *CanvasItem) {
+	if item.NativeAttribute("state") == "hidden" {
+		return
+	}
+	pts := r.points(item.Coords())
+	switch item.Type() {
+	case CanvasItemTypeLine:
+		r.strokePolyline(pts, false, r.lineWidth(item), r.color(item.NativeAttribute("fill")))
+	case CanvasItemTypeRectangle:
+		if len(pts) != 2 {
+			return
+		}
+		rect := []canvasPoint{pts[0], {pts[1].X, pts[0].Y}, pts[1], {pts[0].X, pts[1].Y}}
+		r.fillPolygon(rect, r.color(item.NativeAttribute("fill")))
+		r.strokePolyline(rect, true, r.lineWidth(item), r.color(item.NativeAttribute("outline")))
+	case CanvasItemTypeOval:
+		if len(pts) != 2 {
+			return
+		}
+		oval := ellipsePoints(pts[0], pts[1], 0, 360)
+		r.fillPolygon(oval, r.color(item.NativeAttribute("fill")))
+		r.strokePolyline(oval, true, r.lineWidth(item), r.color(item.NativeAttribute("outline")))
+	case CanvasItemTypeArc:
+		if len(pts) != 2 {
+			return
+		}
+		start, _ := strconv.ParseFloat(item.NativeAttribute("start"), 64)
+		extent, _ := strconv.ParseFloat(item.NativeAttribute("extent"), 64)
+		arc := ellipsePoints(pts[0], pts[1], start, extent)
+		switch item.NativeAttribute("style") {
+		case "pieslice":
+			arc = append(arc, canvasPoint{(pts[0].X + pts[1].X) / 2, (pts[0].Y + pts[1].Y) / 2})
+			fallthrough
+		case "chord":
+			r.fillPolygon(arc, r.color(item.NativeAttribute("fill")))
+			r.strokePolyline(arc, true, r.lineWidth(item), r.color(item.NativeAttribute("outline")))
+		default:
+			r.strokePolyline(arc, false, r.lineWidth(item), r.color(item.NativeAttribute("outline")))
+		}
+	case CanvasItemTypePolygon:
+		r.fillPolygon(pts, r.color(item.NativeAttribute("fill")))
+		r.strokePolyline(pts, true, r.lineWidth(item), r.color(item.NativeAttribute("outline")))
+	case CanvasItemTypeImage:
+		if len(pts) != 1 {
+			return
+		}
+		im := parserImageResult(item.NativeAttribute("image"), nil)
+		if im == nil {
+			return
+		}
+		src := im.ToImage()
+		if src == nil {
+			return
+		}
+		x1, y1, _, _ := item.BBoxN()
+		dp := image.Pt(x1-int(r.ox), y1-int(r.oy))
+		draw.Draw(r.img, src.Bounds().Sub(src.Bounds().Min).Add(dp), src, src.Bounds().Min, draw.Over)
+	}
+}
+
+// points of ellipse in box p1,p2 from start degrees with extent degrees
+func ellipsePoints(p1 canvasPoint, p2 canvasPoint, start float64, extent float64) (pts []canvasPoint) {
+	cx, cy := (p1.X+p2.X)/2, (p1.Y+p2.Y)/2
+	rx, ry := math.Abs(p2.X-p1.X)/2, math.Abs(p2.Y-p1.Y)/2
+	steps := int(math.Ceil(math.Abs(extent) / 5))
+	if steps < 2 {
+		steps = 2
+	}
+	for i := 0; i <= steps; i++ {
+		a := (start + extent*float64(i)/float64(steps)) * math.Pi / 180
+		pts = append(pts, canvasPoint{cx + rx*math.Cos(a), cy - ry*math.Sin(a)})
+	}
+	return
+}
+
+// fill polygon with even-odd rule, sample at pixel center
+func (r *canvasRender) fillPolygon(pts []canvasPoint, clr color.Color) {
+	if clr == nil || len(pts) < 3 {
+		return
+	}
+	bounds := r.img.Bounds()
+	minY, maxY := pts[0].Y, pts[0].Y
+	for _, p := range pts {
+		minY = math.Min(minY, p.Y)
+		maxY = math.Max(maxY, p.Y)
+	}
+	y0 := int(math.Max(math.Floor(minY), float64(bounds.Min.Y)))
+	y1 := int(math.Min(math.Ceil(maxY), float64(bounds.Max.Y-1)))
+	var xs []float64
+	for y := y0; y <= y1; y++ {
+		sy := float64(y) + 0.5
+		xs = xs[:0]
+		for i := range pts {
+			a, b := pts[i], pts[(i+1)%len(pts)]
+			if (a.Y <= sy && b.Y > sy) || (b.Y <= sy && a.Y > sy) {
+				xs = append(xs, a.X+(sy-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := int(math.Max(math.Ceil(xs[i]-0.5), float64(bounds.Min.X)))
+			x1 := int(math.Min(math.Floor(xs[i+1]-0.5), float64(bounds.Max.X-1)))
+			for x := x0; x <= x1; x++ {
+				r.img.Set(x, y, clr)
+			}
+		}
+	}
+}
+
+// stroke each segment as a quad, join segments with squares
+func (r *canvasRender) strokePolyline(pts []canvasPoint, closed bool, width float64, clr color.Color) {
+	if clr == nil || len(pts) < 2 {
+		return
+	}
+	if closed {
+		pts = append(pts, pts[0])
+	}
+	hw := math.Max(width, 1) / 2
+	for i := 0; i+1 < len(pts); i++ {
+		a, b := pts[i], pts[i+1]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		l := math.Hypot(dx, dy)
+		if l == 0 {
+			continue
+		}
+		nx, ny := -dy/l*hw, dx/l*hw
+		r.fillPolygon([]canvasPoint{{a.X + nx, a.Y + ny}, {b.X + nx, b.Y + ny}, {b.X - nx, b.Y - ny}, {a.X - nx, a.Y - ny}}, clr)
+		if i > 0 && hw > 1 {
+			r.fillPolygon([]canvasPoint{{a.X - hw, a.Y - hw}, {a.X + hw, a.Y - hw}, {a.X + hw, a.Y + hw}, {a.X - hw, a.Y + hw}}, clr)
+		}
+	}
+}
@@ -0,0 +1,645 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type CanvasItemType int
+
+const (
+	CanvasItemTypeNone CanvasItemType = iota
+	CanvasItemTypeLine
+	CanvasItemTypeRectangle
+	CanvasItemTypeOval
+	CanvasItemTypeArc
+	CanvasItemTypePolygon
+	CanvasItemTypeText
+	CanvasItemTypeImage
+	CanvasItemTypeWindow
+	CanvasItemTypeBitmap
+)
+
+var (
+	canvasItemTypeName = []string{"", "line", "rectangle", "oval", "arc", "polygon", "text", "image", "window", "bitmap"}
+)
+
+func (v CanvasItemType) String() string {
+	if v >= 0 && int(v) < len(canvasItemTypeName) {
+		return canvasItemTypeName[v]
+	}
+	return ""
+}
+
+func parserCanvasItemTypeResult(r string, err error) CanvasItemType {
+	if err != nil {
+		return CanvasItemTypeNone
+	}
+	for n, s := range canvasItemTypeName {
+		if s == r {
+			return CanvasItemType(n)
+		}
+	}
+	return CanvasItemTypeNone
+}
+
+type ArcStyle int
+
+const (
+	ArcStylePieSlice ArcStyle = iota
+	ArcStyleChord
+	ArcStyleArc
+)
+
+var (
+	arcStyleName = []string{"pieslice", "chord", "arc"}
+)
+
+func (v ArcStyle) String() string {
+	if v >= 0 && int(v) < len(arcStyleName) {
+		return arcStyleName[v]
+	}
+	return ""
+}
+
+type ArrowStyle int
+
+const (
+	ArrowStyleNone ArrowStyle = iota
+	ArrowStyleFirst
+	ArrowStyleLast
+	ArrowStyleBoth
+)
+
+var (
+	arrowStyleName = []string{"none", "first", "last", "both"}
+)
+
+func (v ArrowStyle) String() string {
+	if v >= 0 && int(v) < len(arrowStyleName) {
+		return arrowStyleName[v]
+	}
+	return ""
+}
+
+type CapStyle int
+
+const (
+	CapStyleButt CapStyle = iota
+	CapStyleProjecting
+	CapStyleRound
+)
+
+var (
+	capStyleName = []string{"butt", "projecting", "round"}
+)
+
+func (v CapStyle) String() string {
+	if v >= 0 && int(v) < len(capStyleName) {
+		return capStyleName[v]
+	}
+	return ""
+}
+
+type JoinStyle int
+
+const (
+	JoinStyleRound JoinStyle = iota
+	JoinStyleBevel
+	JoinStyleMiter
+)
+
+var (
+	joinStyleName = []string{"round", "bevel", "miter"}
+)
+
+func (v JoinStyle) String() string {
+	if v >= 0 && int(v) < len(joinStyleName) {
+		return joinStyleName[v]
+	}
+	return ""
+}
+
+// canvas item attribute
+type CanvasItemAttr struct {
+	Key   string
+	Value interface{}
+}
+
+func buildCanvasItemAttributeScript(attributes []*CanvasItemAttr) string {
+	var list []string
+	for _, attr := range attributes {
+		if attr == nil {
+			continue
+		}
+		if strs, ok := attr.Value.([]string); ok {
+			pname := "atk_tmp_" + attr.Key
+			setObjTextList(pname, strs)
+			list = append(list, fmt.Sprintf("-%v $%v", attr.Key, pname))
+			continue
+		}
+		if s, ok := attr.Value.(string); ok {
+			pname := "atk_tmp_" + attr.Key
+			setObjText(pname, s)
+			list = append(list, fmt.Sprintf("-%v $%v", attr.Key, pname))
+			continue
+		}
+		list = append(list, fmt.Sprintf("-%v {%v}", attr.Key, attr.Value))
+	}
+	return strings.Join(list, " ")
+}
+
+func coordsToString(coords []float64) string {
+	var list []string
+	for _, v := range coords {
+		list = append(list, strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return strings.Join(list, " ")
+}
+
+type CanvasItem struct {
+	canvas *Canvas
+	id     string
+}
+
+func (i *CanvasItem) Id() string {
+	return i.id
+}
+
+func (i *CanvasItem) Canvas() *Canvas {
+	return i.canvas
+}
+
+func (i *CanvasItem) String() string {
+	return fmt.Sprintf("CanvasItem{%v}", i.id)
+}
+
+func (i *CanvasItem) IsValid() bool {
+	if i == nil || i.canvas == nil || i.id == "" {
+		return false
+	}
+	r, err := evalAsString(fmt.Sprintf("%v find withtag {%v}", i.canvas.id, i.id))
+	return err == nil && r != ""
+}
+
+func (i *CanvasItem) Type() CanvasItemType {
+	r, err := evalAsString(fmt.Sprintf("%v type {%v}", i.canvas.id, i.id))
+	return parserCanvasItemTypeResult(r, err)
+}
+
+func (i *CanvasItem) SetCoords(coords ...float64) error {
+	if len(coords) < 2 {
+		return ErrInvalid
+	}
+	return eval(fmt.Sprintf("%v coords {%v} {%v}", i.canvas.id, i.id, coordsToString(coords)))
+}
+
+func (i *CanvasItem) Coords() []float64 {
+	r, err := evalAsStringList(fmt.Sprintf("%v coords {%v}", i.canvas.id, i.id))
+	if err != nil {
+		return nil
+	}
+	var coords []float64
+	for _, s := range r {
+		v, _ := strconv.ParseFloat(s, 64)
+		coords = append(coords, v)
+	}
+	return coords
+}
+
+func (i *CanvasItem) Move(dx float64, dy float64) error {
+	return eval(fmt.Sprintf("%v move {%v} %v %v", i.canvas.id, i.id, dx, dy))
+}
+
+// Move the item so that its first coordinate is at x,y (tk8.6)
+func (i *CanvasItem) MoveTo(x float64, y float64) error {
+	if !mainInterp.SupportTk86() {
+		return ErrUnsupport
+	}
+	return eval(fmt.Sprintf("%v moveto {%v} %v %v", i.canvas.id, i.id, x, y))
+}
+
+func (i *CanvasItem) Scale(xOrigin float64, yOrigin float64, xScale float64, yScale float64) error {
+	return eval(fmt.Sprintf("%v scale {%v} %v %v %v %v", i.canvas.id, i.id, xOrigin, yOrigin, xScale, yScale))
+}
+
+func (i *CanvasItem) Configure(attributes ...*CanvasItemAttr) error {
+	extra := buildCanvasItemAttributeScript(attributes)
+	if len(extra) == 0 {
+		return nil
+	}
+	return eval(fmt.Sprintf("%v itemconfigure {%v} %v", i.canvas.id, i.id, extra))
+}
+
+func (i *CanvasItem) NativeAttribute(key string) string {
+	r, _ := evalAsString(fmt.Sprintf("%v itemcget {%v} -%v", i.canvas.id, i.id, key))
+	return r
+}
+
+func (i *CanvasItem) SetNativeAttribute(key string, value string) error {
+	return i.Configure(&CanvasItemAttr{key, value})
+}
+
+func (i *CanvasItem) Raise(above *CanvasItem) error {
+	script := fmt.Sprintf("%v raise {%v}", i.canvas.id, i.id)
+	if above != nil {
+		script += fmt.Sprintf(" {%v}", above.id)
+	}
+	return eval(script)
+}
+
+func (i *CanvasItem) Lower(below *CanvasItem) error {
+	script := fmt.Sprintf("%v lower {%v}", i.canvas.id, i.id)
+	if below != nil {
+		script += fmt.Sprintf(" {%v}", below.id)
+	}
+	return eval(script)
+}
+
+func (i *CanvasItem) Delete() error {
+	return eval(fmt.Sprintf("%v delete {%v}", i.canvas.id, i.id))
+}
+
+func (i *CanvasItem) BBoxN() (x1 int, y1 int, x2 int, y2 int) {
+	return i.canvas.BBoxN(i)
+}
+
+func (i *CanvasItem) BBox() Geometry {
+	return i.canvas.BBox(i)
+}
+
+func (i *CanvasItem) AddTag(tags ...string) error {
+	for _, tag := range tags {
+		if tag == "" {
+			return ErrInvalid
+		}
+		setObjText("atk_tmp_tag", tag)
+		err := eval(fmt.Sprintf("%v addtag $atk_tmp_tag withtag {%v}", i.canvas.id, i.id))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *CanvasItem) RemoveTag(tag string) error {
+	if tag == "" {
+		return ErrInvalid
+	}
+	setObjText("atk_tmp_tag", tag)
+	return eval(fmt.Sprintf("%v dtag {%v} $atk_tmp_tag", i.canvas.id, i.id))
+}
+
+func (i *CanvasItem) SetTags(tags []string) error {
+	return i.Configure(CanvasItemAttrTags(tags))
+}
+
+func (i *CanvasItem) Tags() []string {
+	r, _ := evalAsStringList(fmt.Sprintf("%v gettags {%v}", i.canvas.id, i.id))
+	return r
+}
+
+func (i *CanvasItem) HasTag(tag string) bool {
+	for _, v := range i.Tags() {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// add bind event for the item, event is one of Enter, Leave, ButtonPress,
+// Motion, ButtonRelease, KeyPress, KeyRelease or virtual events
+func (i *CanvasItem) BindEvent(event string, fn func(e *Event)) (*Binding, error) {
+	return i.canvas.BindItemEvent(i.id, event, fn)
+}
+
+func (i *CanvasItem) ClearBind(event string) error {
+	return i.canvas.ClearItemBind(i.id, event)
+}
+
+func (i *CanvasItem) BindInfo() []string {
+	return i.canvas.ItemBindInfo(i.id)
+}
+
+func (w *Canvas) createItem(typ CanvasItemType, coords []float64, extra string, attributes []*CanvasItemAttr) *CanvasItem {
+	script := fmt.Sprintf("%v create %v {%v}", w.id, typ, coordsToString(coords))
+	if extra != "" {
+		script += " " + extra
+	}
+	if attrs := buildCanvasItemAttributeScript(attributes); attrs != "" {
+		script += " " + attrs
+	}
+	r, err := evalAsString(script)
+	if err != nil {
+		return nil
+	}
+	return &CanvasItem{w, r}
+}
+
+func (w *Canvas) CreateLine(coords []float64, attributes ...*CanvasItemAttr) *CanvasItem {
+	if len(coords) < 4 {
+		return nil
+	}
+	return w.createItem(CanvasItemTypeLine, coords, "", attributes)
+}
+
+func (w *Canvas) CreateRectangle(x1 float64, y1 float64, x2 float64, y2 float64, attributes ...*CanvasItemAttr) *CanvasItem {
+	return w.createItem(CanvasItemTypeRectangle, []float64{x1, y1, x2, y2}, "", attributes)
+}
+
+func (w *Canvas) CreateOval(x1 float64, y1 float64, x2 float64, y2 float64, attributes ...*CanvasItemAttr) *CanvasItem {
+	return w.createItem(CanvasItemTypeOval, []float64{x1, y1, x2, y2}, "", attributes)
+}
+
+func (w *Canvas) CreateArc(x1 float64, y1 float64, x2 float64, y2 float64, attributes ...*CanvasItemAttr) *CanvasItem {
+	return w.createItem(CanvasItemTypeArc, []float64{x1, y1, x2, y2}, "", attributes)
+}
+
+func (w *Canvas) CreatePolygon(coords []float64, attributes ...*CanvasItemAttr) *CanvasItem {
+	if len(coords) < 6 {
+		return nil
+	}
+	return w.createItem(CanvasItemTypePolygon, coords, "", attributes)
+}
+
+func (w *Canvas) CreateText(x float64, y float64, text string, attributes ...*CanvasItemAttr) *CanvasItem {
+	setObjText("atk_tmp_itemtext", text)
+	return w.createItem(CanvasItemTypeText, []float64{x, y}, "-text $atk_tmp_itemtext", attributes)
+}
+
+func (w *Canvas) CreateImage(x float64, y float64, image *Image, attributes ...*CanvasItemAttr) *CanvasItem {
+	if image == nil {
+		return nil
+	}
+	return w.createItem(CanvasItemTypeImage, []float64{x, y}, fmt.Sprintf("-image {%v}", image.Id()), attributes)
+}
+
+func (w *Canvas) CreateWindow(x float64, y float64, widget Widget, attributes ...*CanvasItemAttr) *CanvasItem {
+	if !IsValidWidget(widget) {
+		return nil
+	}
+	return w.createItem(CanvasItemTypeWindow, []float64{x, y}, fmt.Sprintf("-window {%v}", widget.Id()), attributes)
+}
+
+func (w *Canvas) Items() []*CanvasItem {
+	return w.parserItemList(evalAsStringList(fmt.Sprintf("%v find all", w.id)))
+}
+
+func (w *Canvas) ItemCount() int {
+	r, _ := evalAsInt(fmt.Sprintf("llength [%v find all]", w.id))
+	return r
+}
+
+func (w *Canvas) DeleteItems(items ...*CanvasItem) error {
+	var ids []string
+	for _, item := range items {
+		if item != nil {
+			ids = append(ids, item.id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return eval(fmt.Sprintf("%v delete %v", w.id, strings.Join(ids, " ")))
+}
+
+func (w *Canvas) DeleteAllItems() error {
+	return eval(fmt.Sprintf("%v delete all", w.id))
+}
+
+func (w *Canvas) parserItemList(ids []string, err error) (list []*CanvasItem) {
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		list = append(list, &CanvasItem{w, id})
+	}
+	return
+}
+
+func (w *Canvas) FindWithTag(tag string) []*CanvasItem {
+	setObjText("atk_tmp_tag", tag)
+	return w.parserItemList(evalAsStringList(fmt.Sprintf("%v find withtag $atk_tmp_tag", w.id)))
+}
+
+// items that overlap the rectangle x1,y1,x2,y2 (canvas coordinates)
+func (w *Canvas) FindOverlapping(x1 float64, y1 float64, x2 float64, y2 float64) []*CanvasItem {
+	return w.parserItemList(evalAsStringList(fmt.Sprintf("%v find overlapping %v %v %v %v", w.id, x1, y1, x2, y2)))
+}
+
+// items completely enclosed by the rectangle x1,y1,x2,y2 (canvas coordinates)
+func (w *Canvas) FindEnclosed(x1 float64, y1 float64, x2 float64, y2 float64) []*CanvasItem {
+	return w.parserItemList(evalAsStringList(fmt.Sprintf("%v find enclosed %v %v %v %v", w.id, x1, y1, x2, y2)))
+}
+
+// topmost item closest to x,y (canvas coordinates); items within halo are treated as overlapping
+func (w *Canvas) FindClosest(x float64, y float64, halo float64) *CanvasItem {
+	r, err := evalAsString(fmt.Sprintf("%v find closest %v %v %v", w.id, x, y, halo))
+	if err != nil || r == "" {
+		return nil
+	}
+	return &CanvasItem{w, r}
+}
+
+// item under the mouse pointer
+func (w *Canvas) CurrentItem() *CanvasItem {
+	r, err := evalAsString(fmt.Sprintf("%v find withtag current", w.id))
+	if err != nil || r == "" {
+		return nil
+	}
+	return &CanvasItem{w, r}
+}
+
+func (w *Canvas) AddTag(tag string, items ...*CanvasItem) error {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		err := item.AddTag(tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove tag from all items
+func (w *Canvas) RemoveTag(tag string) error {
+	if tag == "" {
+		return ErrInvalid
+	}
+	setObjText("atk_tmp_tag", tag)
+	return eval(fmt.Sprintf("%v dtag $atk_tmp_tag", w.id))
+}
+
+// add bind event for all items with tag (or item id), returns binding handle for unbind
+func (w *Canvas) BindItemEvent(tag string, event string, fn func(e *Event)) (*Binding, error) {
+	if tag == "" || !IsEvent(event) || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := newItemBinding(w.id, "bind", tag)
+	err := b.add(event, fn)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (w *Canvas) ClearItemBind(tag string, event string) error {
+	if tag == "" || !IsEvent(event) {
+		return ErrInvalid
+	}
+	setObjText("atk_tmp_tag", tag)
+	return eval(fmt.Sprintf("%v bind $atk_tmp_tag %v {}", w.id, event))
+}
+
+func (w *Canvas) ItemBindInfo(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	setObjText("atk_tmp_tag", tag)
+	r, _ := evalAsStringList(fmt.Sprintf("%v bind $atk_tmp_tag", w.id))
+	return r
+}
+
+// bounding box of items, or all items if empty
+func (w *Canvas) BBoxN(items ...*CanvasItem) (x1 int, y1 int, x2 int, y2 int) {
+	var ids []string
+	for _, item := range items {
+		if item != nil {
+			ids = append(ids, item.id)
+		}
+	}
+	if len(ids) == 0 {
+		ids = append(ids, "all")
+	}
+	r, err := evalAsIntList(fmt.Sprintf("%v bbox %v", w.id, strings.Join(ids, " ")))
+	if err != nil || len(r) != 4 {
+		return
+	}
+	return r[0], r[1], r[2], r[3]
+}
+
+func (w *Canvas) BBox(items ...*CanvasItem) Geometry {
+	x1, y1, x2, y2 := w.BBoxN(items...)
+	return Geometry{x1, y1, x2 - x1, y2 - y1}
+}
+
+func CanvasItemAttrFill(color string) *CanvasItemAttr {
+	return &CanvasItemAttr{"fill", color}
+}
+
+func CanvasItemAttrOutline(color string) *CanvasItemAttr {
+	return &CanvasItemAttr{"outline", color}
+}
+
+func CanvasItemAttrWidth(width float64) *CanvasItemAttr {
+	return &CanvasItemAttr{"width", width}
+}
+
+func CanvasItemAttrDash(pattern string) *CanvasItemAttr {
+	return &CanvasItemAttr{"dash", pattern}
+}
+
+func CanvasItemAttrActiveFill(color string) *CanvasItemAttr {
+	return &CanvasItemAttr{"activefill", color}
+}
+
+func CanvasItemAttrActiveOutline(color string) *CanvasItemAttr {
+	return &CanvasItemAttr{"activeoutline", color}
+}
+
+func CanvasItemAttrDisabledFill(color string) *CanvasItemAttr {
+	return &CanvasItemAttr{"disabledfill", color}
+}
+
+func CanvasItemAttrState(state State) *CanvasItemAttr {
+	return &CanvasItemAttr{"state", state}
+}
+
+func CanvasItemAttrHidden() *CanvasItemAttr {
+	return &CanvasItemAttr{"state", "hidden"}
+}
+
+func CanvasItemAttrTags(tags []string) *CanvasItemAttr {
+	return &CanvasItemAttr{"tags", tags}
+}
+
+func CanvasItemAttrSmooth(smooth bool) *CanvasItemAttr {
+	return &CanvasItemAttr{"smooth", boolToInt(smooth)}
+}
+
+func CanvasItemAttrSplineSteps(steps int) *CanvasItemAttr {
+	return &CanvasItemAttr{"splinesteps", steps}
+}
+
+func CanvasItemAttrArrow(arrow ArrowStyle) *CanvasItemAttr {
+	return &CanvasItemAttr{"arrow", arrow}
+}
+
+func CanvasItemAttrCapStyle(style CapStyle) *CanvasItemAttr {
+	return &CanvasItemAttr{"capstyle", style}
+}
+
+func CanvasItemAttrJoinStyle(style JoinStyle) *CanvasItemAttr {
+	return &CanvasItemAttr{"joinstyle", style}
+}
+
+func CanvasItemAttrArcStart(degrees float64) *CanvasItemAttr {
+	return &CanvasItemAttr{"start", degrees}
+}
+
+func CanvasItemAttrArcExtent(degrees float64) *CanvasItemAttr {
+	return &CanvasItemAttr{"extent", degrees}
+}
+
+func CanvasItemAttrArcStyle(style ArcStyle) *CanvasItemAttr {
+	return &CanvasItemAttr{"style", style}
+}
+
+func CanvasItemAttrText(text string) *CanvasItemAttr {
+	return &CanvasItemAttr{"text", text}
+}
+
+func CanvasItemAttrFont(font Font) *CanvasItemAttr {
+	if font == nil {
+		return nil
+	}
+	return &CanvasItemAttr{"font", font.Id()}
+}
+
+func CanvasItemAttrAnchor(anchor Anchor) *CanvasItemAttr {
+	return &CanvasItemAttr{"anchor", anchor}
+}
+
+func CanvasItemAttrJustify(justify Justify) *CanvasItemAttr {
+	return &CanvasItemAttr{"justify", justify}
+}
+
+// text item line wrap width
+func CanvasItemAttrTextWidth(width int) *CanvasItemAttr {
+	return &CanvasItemAttr{"width", width}
+}
+
+func CanvasItemAttrAngle(degrees float64) *CanvasItemAttr {
+	return &CanvasItemAttr{"angle", degrees}
+}
+
+func CanvasItemAttrImage(image *Image) *CanvasItemAttr {
+	if image == nil {
+		return nil
+	}
+	return &CanvasItemAttr{"image", image.Id()}
+}
+
+func CanvasItemAttrWindowWidth(width int) *CanvasItemAttr {
+	return &CanvasItemAttr{"width", width}
+}
+
+func CanvasItemAttrWindowHeight(height int) *CanvasItemAttr {
+	return &CanvasItemAttr{"height", height}
+}
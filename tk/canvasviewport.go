@@ -0,0 +1,223 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"math"
+)
+
+// CanvasViewport manages zoom and pan of a canvas.
+// World coordinates are the item coordinates at zoom 1,
+// zoom scales all items about the canvas origin.
+// Note: line width and font size of items are not scaled.
+type CanvasViewport struct {
+	canvas    *Canvas
+	zoom      float64
+	minZoom   float64
+	maxZoom   float64
+	region    [4]float64
+	hasRegion bool
+}
+
+func NewCanvasViewport(canvas *Canvas) *CanvasViewport {
+	if !IsValidWidget(canvas) {
+		return nil
+	}
+	return &CanvasViewport{canvas: canvas, zoom: 1, minZoom: 0.01, maxZoom: 100}
+}
+
+func (v *CanvasViewport) Canvas() *Canvas {
+	return v.canvas
+}
+
+// set world scroll region
+func (v *CanvasViewport) SetWorldRegionN(x1 float64, y1 float64, x2 float64, y2 float64) error {
+	v.region = [4]float64{x1, y1, x2, y2}
+	v.hasRegion = true
+	return v.updateScrollRegion()
+}
+
+func (v *CanvasViewport) WorldRegionN() (x1 float64, y1 float64, x2 float64, y2 float64) {
+	if v.hasRegion {
+		return v.region[0], v.region[1], v.region[2], v.region[3]
+	}
+	bx1, by1, bx2, by2 := v.canvas.BBoxN()
+	return float64(bx1) / v.zoom, float64(by1) / v.zoom, float64(bx2) / v.zoom, float64(by2) / v.zoom
+}
+
+// set world scroll region to the bounding box of all items
+func (v *CanvasViewport) FitWorldRegion(margin float64) error {
+	v.hasRegion = false
+	x1, y1, x2, y2 := v.WorldRegionN()
+	return v.SetWorldRegionN(x1-margin, y1-margin, x2+margin, y2+margin)
+}
+
+func (v *CanvasViewport) updateScrollRegion() error {
+	x1, y1, x2, y2 := v.WorldRegionN()
+	return v.canvas.SetScrollRegionN(math.Round(x1*v.zoom), math.Round(y1*v.zoom), math.Round(x2*v.zoom), math.Round(y2*v.zoom))
+}
+
+func (v *CanvasViewport) SetZoomRange(min float64, max float64) error {
+	if min <= 0 || max < min {
+		return ErrInvalid
+	}
+	v.minZoom = min
+	v.maxZoom = max
+	return nil
+}
+
+func (v *CanvasViewport) ZoomRange() (min float64, max float64) {
+	return v.minZoom, v.maxZoom
+}
+
+func (v *CanvasViewport) Zoom() float64 {
+	return v.zoom
+}
+
+// zoom by factor, keep the point at window position x,y fixed
+func (v *CanvasViewport) ZoomAt(factor float64, x int, y int) error {
+	if factor <= 0 {
+		return ErrInvalid
+	}
+	zoom := v.zoom * factor
+	if zoom < v.minZoom {
+		zoom = v.minZoom
+	} else if zoom > v.maxZoom {
+		zoom = v.maxZoom
+	}
+	factor = zoom / v.zoom
+	if factor == 1 {
+		return nil
+	}
+	cx, cy := v.canvas.CanvasX(x), v.canvas.CanvasY(y)
+	err := eval(fmt.Sprintf("%v scale all 0 0 %v %v", v.canvas.id, factor, factor))
+	if err != nil {
+		return err
+	}
+	v.zoom = zoom
+	err = v.updateScrollRegion()
+	if err != nil {
+		return err
+	}
+	return v.scrollTo(cx*factor-float64(x), cy*factor-float64(y))
+}
+
+// set zoom, keep the center of window fixed
+func (v *CanvasViewport) SetZoom(zoom float64) error {
+	if zoom <= 0 {
+		return ErrInvalid
+	}
+	width, _ := evalAsInt(fmt.Sprintf("winfo width %v", v.canvas.id))
+	height, _ := evalAsInt(fmt.Sprintf("winfo height %v", v.canvas.id))
+	return v.ZoomAt(zoom/v.zoom, width/2, height/2)
+}
+
+func (v *CanvasViewport) ResetZoom() error {
+	return v.SetZoom(1)
+}
+
+// scroll the view so that canvas position left,top is at the window origin
+func (v *CanvasViewport) scrollTo(left float64, top float64) error {
+	x1, y1, x2, y2 := v.canvas.ScrollRegionN()
+	if x2 > x1 {
+		err := v.canvas.XViewMoveTo((left - x1) / (x2 - x1))
+		if err != nil {
+			return err
+		}
+	}
+	if y2 > y1 {
+		return v.canvas.YViewMoveTo((top - y1) / (y2 - y1))
+	}
+	return nil
+}
+
+// scroll the view so that world position wx,wy is at the window center
+func (v *CanvasViewport) CenterOn(wx float64, wy float64) error {
+	width, _ := evalAsInt(fmt.Sprintf("winfo width %v", v.canvas.id))
+	height, _ := evalAsInt(fmt.Sprintf("winfo height %v", v.canvas.id))
+	return v.scrollTo(wx*v.zoom-math.Round(float64(width)/2), wy*v.zoom-math.Round(float64(height)/2))
+}
+
+// window coordinates to canvas coordinates
+func (v *CanvasViewport) WindowToCanvas(x int, y int) (float64, float64) {
+	return v.canvas.CanvasX(x), v.canvas.CanvasY(y)
+}
+
+// window coordinates to world coordinates
+func (v *CanvasViewport) WindowToWorld(x int, y int) (float64, float64) {
+	cx, cy := v.WindowToCanvas(x, y)
+	return cx / v.zoom, cy / v.zoom
+}
+
+// world coordinates to window coordinates
+func (v *CanvasViewport) WorldToWindow(wx float64, wy float64) (int, int) {
+	left, top := v.WindowToCanvas(0, 0)
+	return int(math.Round(wx*v.zoom - left)), int(math.Round(wy*v.zoom - top))
+}
+
+// world coordinates to canvas coordinates
+func (v *CanvasViewport) WorldToCanvas(wx float64, wy float64) (float64, float64) {
+	return wx * v.zoom, wy * v.zoom
+}
+
+// zoom around the mouse pointer with the mouse wheel, step is the zoom factor of one wheel step
+func (v *CanvasViewport) EnableWheelZoom(step float64) error {
+	if step <= 1 {
+		return ErrInvalid
+	}
+	err := v.canvas.BindEvent("<MouseWheel>", func(e *Event) {
+		if e.WheelDelta > 0 {
+			v.ZoomAt(step, e.PosX, e.PosY)
+		} else if e.WheelDelta < 0 {
+			v.ZoomAt(1/step, e.PosX, e.PosY)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	// x11 wheel is button 4 and 5 before Tk 8.7, later versions send <MouseWheel>
+	if WindowingSystem() != "x11" {
+		return nil
+	}
+	if r, _ := evalAsInt("package vcompare $tk_version 8.7"); r >= 0 {
+		return nil
+	}
+	err = v.canvas.BindEvent("<ButtonPress-4>", func(e *Event) {
+		v.ZoomAt(step, e.PosX, e.PosY)
+	})
+	if err != nil {
+		return err
+	}
+	return v.canvas.BindEvent("<ButtonPress-5>", func(e *Event) {
+		v.ZoomAt(1/step, e.PosX, e.PosY)
+	})
+}
+
+// pan the view by dragging with mouse button (usually 2 for middle button)
+func (v *CanvasViewport) EnablePan(button int) error {
+	if button < 1 || button > 5 {
+		return ErrInvalid
+	}
+	err := v.canvas.BindEvent(fmt.Sprintf("<ButtonPress-%v>", button), func(e *Event) {
+		v.canvas.ScanMark(e.PosX, e.PosY)
+	})
+	if err != nil {
+		return err
+	}
+	return v.canvas.BindEvent(fmt.Sprintf("<B%v-Motion>", button), func(e *Event) {
+		v.canvas.ScanDragTo(e.PosX, e.PosY, 1)
+	})
+}
+
+func (v *CanvasViewport) EnableMiddleButtonPan() error {
+	return v.EnablePan(2)
+}
+
+func (v *CanvasViewport) BindXScrollBar(bar *ScrollBar) error {
+	return v.canvas.BindXScrollBar(bar)
+}
+
+func (v *CanvasViewport) BindYScrollBar(bar *ScrollBar) error {
+	return v.canvas.BindYScrollBar(bar)
+}
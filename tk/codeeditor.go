@@ -0,0 +1,489 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	codeEditorCurrentLineTag = "atk_code_currentline"
+	codeEditorBracketTag     = "atk_code_bracket"
+	codeEditorBracketLimit   = 20000
+)
+
+var (
+	codeEditorTokenColors = []string{"#0000c0", "#008080", "#a31515", "#098658", "#008000", "#0451a5", "#795e26"}
+)
+
+func codeTokenTag(typ CodeTokenType) string {
+	return "atk_code_" + typ.String()
+}
+
+// code editor with line number gutter, current line highlight, bracket matching,
+// auto indent and incremental syntax highlighting by CodeLexer.
+// Edits by the text widget command are highlighted on idle, the text
+// modified flag is not changed by the editor.
+type CodeEditor struct {
+	*ScrollLayout
+	*Text
+	Gutter          *Canvas
+	lexer           CodeLexer
+	states          []int
+	dirtyStart      int
+	dirtyEnd        int
+	fullHighlight   bool
+	pending         bool
+	idleAct         string
+	onChanged       Command
+	lineNumbers     bool
+	gutterColor     string
+	currentLine     bool
+	matchBrackets   bool
+	autoIndent      bool
+	indent          string
+	gutterLineCount int
+}
+
+func NewCodeEditor(parent Widget, attributes ...*WidgetAttr) *CodeEditor {
+	w := &CodeEditor{lineNumbers: true, gutterColor: "#808080", currentLine: true, matchBrackets: true, autoIndent: true, indent: "\t",
+		dirtyStart: -1, dirtyEnd: -1}
+	w.ScrollLayout = NewScrollLayout(parent)
+	w.Text = NewText(parent, append([]*WidgetAttr{TextAttrLineWrap(LineWrapNone), TextAttrEnableUndo(true)}, attributes...)...)
+	w.Gutter = NewCanvas(parent, CanvasAttrWidth(30), CanvasAttrHighlightthickness(0), CanvasAttrBorderWidth(0), CanvasAttrTakeFocus(false))
+	w.AddWidget(w.Gutter, GridAttrRow(0), GridAttrColumn(0), GridAttrSticky(StickyNS))
+	w.AddWidget(w.Text, GridAttrRow(0), GridAttrColumn(1), GridAttrSticky(StickyAll))
+	w.AddWidget(w.YScrollBar, GridAttrRow(0), GridAttrColumn(2), GridAttrSticky(StickyNS))
+	w.AddWidget(w.XScrollBar, GridAttrRow(1), GridAttrColumn(1), GridAttrSticky(StickyEW))
+	w.SetRowAttr(0, 0, 1, "")
+	w.SetColumnAttr(1, 0, 1, "")
+	w.Text.BindXScrollBar(w.XScrollBar)
+	w.Text.BindYScrollBar(w.YScrollBar)
+	w.Text.OnYScrollEx(func([]string) error {
+		w.updateGutter()
+		return nil
+	})
+	for n, color := range codeEditorTokenColors {
+		w.Text.CreateTag(codeTokenTag(CodeTokenType(n)), TextTagAttrForeground(color))
+	}
+	w.Text.CreateTag(codeEditorCurrentLineTag, TextTagAttrBackground("#f0f0ff")).Lower(nil)
+	w.Text.CreateTag(codeEditorBracketTag, TextTagAttrBackground("#c0e0c0"))
+	w.Text.BindEvent("<KeyRelease>", func(e *Event) {
+		w.updateCursor()
+	})
+	w.Text.BindEvent("<ButtonRelease-1>", func(e *Event) {
+		w.updateCursor()
+	})
+	act := makeActionId()
+	mainInterp.CreateAction(act, func([]string) {
+		w.insertNewline()
+	})
+	addWidgetAction(w.Text.id, act)
+	eval(fmt.Sprintf("bind %v <Return> {%v; break}", w.Text.id, act))
+	w.wrapEdit()
+	RegisterWidget(w)
+	return w
+}
+
+// set lexer for syntax highlighting, nil to disable highlighting
+func (w *CodeEditor) SetLexer(lexer CodeLexer) error {
+	w.lexer = lexer
+	w.states = nil
+	for n := range codeEditorTokenColors {
+		w.Text.Tag(codeTokenTag(CodeTokenType(n))).Clear()
+	}
+	return w.Highlight()
+}
+
+func (w *CodeEditor) Lexer() CodeLexer {
+	return w.lexer
+}
+
+// set text tag style of token type
+func (w *CodeEditor) SetTokenStyle(typ CodeTokenType, attributes ...*TextTagAttr) error {
+	if typ.String() == "" {
+		return ErrInvalid
+	}
+	return w.Text.Tag(codeTokenTag(typ)).Configure(attributes...)
+}
+
+// wrap text widget command to find changed lines of insert, delete and replace,
+// other subcommands call the widget command directly
+func (w *CodeEditor) wrapEdit() {
+	act := makeActionId()
+	mainInterp.CreateAction(act, func(args []string) {
+		w.recordEdit(args)
+	})
+	addWidgetAction(w.Text.id, act)
+	idle := makeActionId()
+	mainInterp.CreateAction(idle, func([]string) {
+		w.pending = false
+		w.Highlight()
+		w.updateCursor()
+		w.onChanged.Invoke()
+	})
+	addWidgetAction(w.Text.id, idle)
+	w.idleAct = idle
+	eval(`proc ::atk_code_line {w index} {
+	if {[$w compare $index > "end -1c"]} {set index "end -1c"}
+	lindex [split [$w index $index] .] 0
+}
+proc ::atk_code_edit {act orig args} {
+	switch -- [lindex $args 0] {
+		insert - delete - replace {}
+		edit {
+			set r [$orig {*}$args]
+			if {[lindex $args 1] in {undo redo}} {$act full}
+			return $r
+		}
+		default {tailcall $orig {*}$args}
+	}
+	set sub [lindex $args 0]
+	if {[catch {
+		set first [::atk_code_line $orig [lindex $args 1]]
+		set removed 0
+		set text ""
+		if {$sub eq "insert"} {
+			foreach {chars tags} [lrange $args 2 end] {append text $chars}
+		} else {
+			if {$sub eq "delete" && [llength $args] > 3} {error "multiple ranges"}
+			set last [lindex $args 2]
+			if {[llength $args] == 2} {set last "[lindex $args 1] +1c"}
+			set removed [expr {max(0, [::atk_code_line $orig $last] - $first)}]
+			if {$sub eq "replace"} {
+				foreach {chars tags} [lrange $args 3 end] {append text $chars}
+			}
+		}
+		set added [regexp -all {\n} $text]
+	}]} {
+		set edit full
+	} else {
+		set edit [list $first $removed $added]
+	}
+	set r [$orig {*}$args]
+	$act {*}$edit
+	return $r
+}`)
+	// the alias is deleted with widget actions when the widget is destroyed
+	orig := "::atk_code_orig" + w.Text.id
+	eval(fmt.Sprintf("rename %[1]v %[2]v; interp alias {} %[1]v {} ::atk_code_edit %[3]v %[2]v", w.Text.id, orig, act))
+	addWidgetAction(w.Text.id, w.Text.id)
+}
+
+// edit at line first (1-based) replaced removed+1 lines with added+1 lines,
+// keep line states in line and mark changed lines dirty, highlight is scheduled on idle
+func (w *CodeEditor) recordEdit(args []string) {
+	if !w.pending {
+		w.pending = true
+		eval(fmt.Sprintf("after idle {if {[info commands %[1]v] ne {}} %[1]v}", w.idleAct))
+	}
+	if w.fullHighlight || w.states == nil {
+		return
+	}
+	if len(args) != 3 {
+		w.fullHighlight = true
+		return
+	}
+	first, removed, added := eventInt(args[0])-1, eventInt(args[1]), eventInt(args[2])
+	if first < 0 || first+removed >= len(w.states) {
+		w.fullHighlight = true
+		return
+	}
+	states := make([]int, 0, len(w.states)+added-removed)
+	states = append(states, w.states[:first]...)
+	for i := 0; i <= added; i++ {
+		states = append(states, -1)
+	}
+	w.states = append(states, w.states[first+removed+1:]...)
+	shift := func(line int) int {
+		if line > first+removed {
+			return line + added - removed
+		} else if line > first {
+			return first + added
+		}
+		return line
+	}
+	if w.dirtyStart == -1 {
+		w.dirtyStart, w.dirtyEnd = first, first+added
+		return
+	}
+	w.dirtyStart, w.dirtyEnd = shift(w.dirtyStart), shift(w.dirtyEnd)
+	if first < w.dirtyStart {
+		w.dirtyStart = first
+	}
+	if first+added > w.dirtyEnd {
+		w.dirtyEnd = first + added
+	}
+}
+
+// highlight lines changed since last highlight, editor calls it on idle after edits.
+// lines after the changed lines are highlighted until the lexer state is same as before.
+func (w *CodeEditor) Highlight() error {
+	full := w.fullHighlight || w.states == nil
+	start, end := w.dirtyStart, w.dirtyEnd
+	w.fullHighlight = false
+	w.dirtyStart, w.dirtyEnd = -1, -1
+	if w.lexer == nil {
+		w.states = nil
+		return nil
+	}
+	count := w.Text.LineCount()
+	if !full && len(w.states) != count {
+		full = true
+	}
+	if full {
+		w.states = make([]int, count)
+		for i := range w.states {
+			w.states[i] = -1
+		}
+		start, end = 0, count-1
+	} else if start == -1 {
+		return nil
+	}
+	if end >= count {
+		end = count - 1
+	}
+	lines := strings.Split(w.Text.Get(NewTextIndex(start+1, 0), NewTextIndex(end+1, 0).LineEnd()), "\n")
+	state := 0
+	if start > 0 {
+		state = w.states[start-1]
+	}
+	var script []string
+	for i := start; i < count; i++ {
+		var line string
+		if i-start < len(lines) {
+			line = lines[i-start]
+		} else {
+			line = w.Text.Get(NewTextIndex(i+1, 0), NewTextIndex(i+1, 0).LineEnd())
+		}
+		var tokens []CodeToken
+		tokens, state = w.lexer.LexLine(line, state)
+		script = append(script, w.lineTagScript(i+1, tokens))
+		old := w.states[i]
+		w.states[i] = state
+		// next lines are not changed and start with same state
+		if i >= end && state == old {
+			break
+		}
+	}
+	if len(script) == 0 {
+		return nil
+	}
+	return eval(strings.Join(script, "\n"))
+}
+
+func (w *CodeEditor) lineTagScript(line int, tokens []CodeToken) string {
+	var tags []string
+	for n := range codeEditorTokenColors {
+		tags = append(tags, codeTokenTag(CodeTokenType(n)))
+	}
+	script := fmt.Sprintf("foreach atk_tmp_tag {%v} {%v tag remove $atk_tmp_tag %v.0 %v.end}", strings.Join(tags, " "), w.Text.id, line, line)
+	for _, t := range tokens {
+		if t.End <= t.Start {
+			continue
+		}
+		script += fmt.Sprintf("\n%v tag add %v %v.%v %v.%v", w.Text.id, codeTokenTag(t.Type), line, t.Start, line, t.End)
+	}
+	return script
+}
+
+// text changed, called after highlighting
+func (w *CodeEditor) OnTextChanged(fn func()) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	w.onChanged.Bind(fn)
+	return nil
+}
+
+func (w *CodeEditor) SetShowLineNumbers(show bool) error {
+	w.lineNumbers = show
+	if show {
+		err := w.AddWidget(w.Gutter, GridAttrRow(0), GridAttrColumn(0), GridAttrSticky(StickyNS))
+		w.updateGutter()
+		return err
+	}
+	return w.RemoveWidget(w.Gutter)
+}
+
+func (w *CodeEditor) IsShowLineNumbers() bool {
+	return w.lineNumbers
+}
+
+func (w *CodeEditor) SetLineNumberColor(color string) error {
+	w.gutterColor = color
+	w.updateGutter()
+	return nil
+}
+
+func (w *CodeEditor) LineNumberColor() string {
+	return w.gutterColor
+}
+
+func (w *CodeEditor) SetHighlightCurrentLine(b bool) error {
+	w.currentLine = b
+	w.updateCursor()
+	return nil
+}
+
+func (w *CodeEditor) IsHighlightCurrentLine() bool {
+	return w.currentLine
+}
+
+func (w *CodeEditor) SetCurrentLineBackground(color string) error {
+	return w.Text.Tag(codeEditorCurrentLineTag).Configure(TextTagAttrBackground(color))
+}
+
+func (w *CodeEditor) SetMatchBrackets(b bool) error {
+	w.matchBrackets = b
+	w.updateCursor()
+	return nil
+}
+
+func (w *CodeEditor) IsMatchBrackets() bool {
+	return w.matchBrackets
+}
+
+func (w *CodeEditor) SetBracketBackground(color string) error {
+	return w.Text.Tag(codeEditorBracketTag).Configure(TextTagAttrBackground(color))
+}
+
+func (w *CodeEditor) SetAutoIndent(b bool) error {
+	w.autoIndent = b
+	return nil
+}
+
+func (w *CodeEditor) IsAutoIndent() bool {
+	return w.autoIndent
+}
+
+// indent text for auto indent after open bracket, default is tab
+func (w *CodeEditor) SetIndentText(indent string) error {
+	w.indent = indent
+	return nil
+}
+
+func (w *CodeEditor) IndentText() string {
+	return w.indent
+}
+
+// insert newline and indent like tk::TextInsert, the selection around insert
+// cursor is replaced and the edit is undone in one step
+func (w *CodeEditor) insertNewline() {
+	auto := w.Text.IsAutoSeparatorsOnUndo()
+	if auto {
+		w.Text.SetAutoSeparatorsOnUndo(false)
+		w.Text.AddUndoSeparator()
+	}
+	eval(fmt.Sprintf(`if {[%[1]v tag nextrange sel 1.0 end] ne "" && [%[1]v compare sel.first <= insert] && [%[1]v compare sel.last >= insert]} {
+	%[1]v delete sel.first sel.last
+}`, w.Text.id))
+	text := "\n"
+	if w.autoIndent {
+		line := w.Text.Get(TextIndexInsert.LineStart(), TextIndexInsert)
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if trim := strings.TrimRight(line, " \t"); trim != "" {
+			switch trim[len(trim)-1] {
+			case '{', '(', '[', ':':
+				indent += w.indent
+			}
+		}
+		text += indent
+	}
+	setObjText("atk_text_insert", text)
+	eval(fmt.Sprintf("%v insert insert $atk_text_insert\n%v see insert", w.Text.id, w.Text.id))
+	if auto {
+		w.Text.AddUndoSeparator()
+		w.Text.SetAutoSeparatorsOnUndo(true)
+	}
+}
+
+func (w *CodeEditor) updateCursor() {
+	w.updateCurrentLine()
+	w.updateBracket()
+}
+
+func (w *CodeEditor) updateCurrentLine() {
+	tag := w.Text.Tag(codeEditorCurrentLineTag)
+	tag.Clear()
+	if w.currentLine {
+		tag.AddRange(TextIndexInsert.LineStart(), TextIndexInsert.LineEnd().Chars(1))
+	}
+}
+
+func (w *CodeEditor) updateBracket() {
+	tag := w.Text.Tag(codeEditorBracketTag)
+	tag.Clear()
+	if !w.matchBrackets {
+		return
+	}
+	insert := w.Text.InsertCursor()
+	for _, index := range []TextIndex{w.Text.Index(insert.Chars(-1)), insert} {
+		if match := w.MatchBracket(index); match != "" {
+			tag.AddRange(index, "")
+			tag.AddRange(match, "")
+			return
+		}
+	}
+}
+
+// index of bracket matching the bracket at index, empty if not found
+func (w *CodeEditor) MatchBracket(index TextIndex) TextIndex {
+	const pairs = "()[]{}"
+	index = w.Text.Index(index)
+	pos := strings.Index(pairs, w.Text.Get(index, ""))
+	if pos == -1 {
+		return ""
+	}
+	open, close := rune(pairs[pos&^1]), rune(pairs[pos|1])
+	depth := 0
+	if pos%2 == 0 {
+		for n, r := range []rune(w.Text.Get(index.Chars(1), index.Chars(1+codeEditorBracketLimit))) {
+			if r == open {
+				depth++
+			} else if r == close {
+				if depth == 0 {
+					return w.Text.Index(index.Chars(1 + n))
+				}
+				depth--
+			}
+		}
+		return ""
+	}
+	runes := []rune(w.Text.Get(index.Chars(-codeEditorBracketLimit), index))
+	for n := len(runes) - 1; n >= 0; n-- {
+		if runes[n] == close {
+			depth++
+		} else if runes[n] == open {
+			if depth == 0 {
+				return w.Text.Index(index.Chars(n - len(runes)))
+			}
+			depth--
+		}
+	}
+	return ""
+}
+
+func (w *CodeEditor) updateGutter() {
+	if !w.lineNumbers {
+		return
+	}
+	count := w.Text.LineCount()
+	if count != w.gutterLineCount {
+		w.gutterLineCount = count
+		width, _ := evalAsInt(fmt.Sprintf("font measure [%v cget -font] %v", w.Text.id, strings.Repeat("0", len(fmt.Sprint(count))+1)))
+		w.Gutter.SetWidth(width + 8)
+	}
+	setObjText("atk_tmp_color", w.gutterColor)
+	// draw line numbers of visible lines
+	eval(fmt.Sprintf(`%v delete all
+set atk_tmp_x [expr {[%v cget -width] - 4}]
+set atk_tmp_index [%v index "@0,0 linestart"]
+while {[set atk_tmp_info [%v dlineinfo $atk_tmp_index]] ne ""} {
+	%v create text $atk_tmp_x [lindex $atk_tmp_info 1] -anchor ne -text [lindex [split $atk_tmp_index .] 0] -font [%v cget -font] -fill $atk_tmp_color
+	set atk_tmp_next [%v index "$atk_tmp_index +1 lines linestart"]
+	if {$atk_tmp_next eq $atk_tmp_index} break
+	set atk_tmp_index $atk_tmp_next
+}`, w.Gutter.id, w.Gutter.id, w.Text.id, w.Text.id, w.Gutter.id, w.Text.id, w.Text.id))
+}
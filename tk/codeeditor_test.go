@@ -0,0 +1,92 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"testing"
+)
+
+func init() {
+	registerTest("CodeEditor", testCodeEditor)
+}
+
+func testCodeEditor(t *testing.T) {
+	w := NewCodeEditor(nil)
+	defer w.Destroy()
+
+	w.SetText("package main\n/* a\nb */ var s = `x`\nfunc f() {}")
+	w.SetLexer(NewGoLexer())
+	if v := w.TagRanges(codeTokenTag(CodeTokenComment)); len(v) != 2 || v[0].Start != "2.0" || v[1].End != "3.4" {
+		t.Fatal("Highlight comment", v)
+	}
+	if v := w.TagRanges(codeTokenTag(CodeTokenKeyword)); len(v) != 3 {
+		t.Fatal("Highlight keyword", v)
+	}
+	w.Delete("2.0", "2.2")
+	w.Highlight()
+	if v := w.TagRanges(codeTokenTag(CodeTokenComment)); len(v) != 0 {
+		t.Fatal("Highlight dirty", v)
+	}
+	if v := w.TagRanges(codeTokenTag(CodeTokenString)); len(v) != 1 || v[0].Start != "3.13" {
+		t.Fatal("Highlight dirty keyword", v)
+	}
+	// only edited lines and lines with changed lexer state are highlighted
+	w.InsertText("1.0", "// c\n")
+	if w.dirtyStart != 0 || w.dirtyEnd != 1 {
+		t.Fatal("Highlight dirty lines", w.dirtyStart, w.dirtyEnd)
+	}
+	w.Highlight()
+	if v := w.TagRanges(codeTokenTag(CodeTokenComment)); len(v) != 1 || v[0].Start != "1.0" || v[0].End != "1.4" {
+		t.Fatal("Highlight insert", v)
+	}
+	if len(w.states) != w.LineCount() {
+		t.Fatal("Highlight states", len(w.states), w.LineCount())
+	}
+	w.Delete("1.0", "2.0")
+	w.Highlight()
+	if v := w.TagRanges(codeTokenTag(CodeTokenComment)); len(v) != 0 {
+		t.Fatal("Highlight delete", v)
+	}
+	if v := w.MatchBracket("4.9"); v != "4.10" {
+		t.Fatal("MatchBracket", "4.10", v)
+	}
+	if v := w.MatchBracket("4.7"); v != "4.6" {
+		t.Fatal("MatchBracket", "4.6", v)
+	}
+	// edits are highlighted on idle, text modified flag is kept
+	w.SetModified(false)
+	w.InsertText("end", "\n// d")
+	Update()
+	if !w.IsModified() {
+		t.Fatal("IsModified", false)
+	}
+	if v := w.TagRanges(codeTokenTag(CodeTokenComment)); len(v) != 1 || v[0].Start != "5.0" || v[0].End != "5.4" {
+		t.Fatal("Highlight idle", v)
+	}
+	// newline replaces selection and undo as one step
+	w.SetEnableUndo(true)
+	w.ResetUndo()
+	w.Tag("sel").AddRange("4.5", "4.8")
+	w.SetInsertCursor("4.8")
+	w.insertNewline()
+	if v := w.Get("4.0", "5.end"); v != "func \n {}" {
+		t.Fatal("insertNewline", v)
+	}
+	w.Undo()
+	if v := w.Get("4.0", "4.end"); v != "func f() {}" {
+		t.Fatal("insertNewline undo", v)
+	}
+
+	tokens, state := NewJSONLexer().LexLine(`{"a": [1, true, "b"]}`, 0)
+	if state != 0 || len(tokens) != 4 || tokens[0].Type != CodeTokenKey || tokens[3].Type != CodeTokenString {
+		t.Fatal("JSONLexer", tokens)
+	}
+	tokens, _ = NewYAMLLexer().LexLine("- name: 'x' # c", 0)
+	if len(tokens) != 3 || tokens[0].Type != CodeTokenKey || tokens[2].Type != CodeTokenComment {
+		t.Fatal("YAMLLexer", tokens)
+	}
+	tokens, state = NewTclLexer().LexLine(`set a "$b`, 0)
+	if state == 0 || len(tokens) != 2 || tokens[0].Type != CodeTokenKeyword {
+		t.Fatal("TclLexer", tokens, state)
+	}
+}
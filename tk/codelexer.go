@@ -0,0 +1,399 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"strings"
+	"unicode"
+)
+
+type CodeTokenType int
+
+const (
+	CodeTokenKeyword CodeTokenType = iota
+	CodeTokenBuiltin
+	CodeTokenString
+	CodeTokenNumber
+	CodeTokenComment
+	CodeTokenKey
+	CodeTokenVariable
+)
+
+var (
+	codeTokenTypeName = []string{"keyword", "builtin", "string", "number", "comment", "key", "variable"}
+)
+
+func (v CodeTokenType) String() string {
+	if v >= 0 && int(v) < len(codeTokenTypeName) {
+		return codeTokenTypeName[v]
+	}
+	return ""
+}
+
+// code token in line, Start and End are rune offsets
+type CodeToken struct {
+	Type  CodeTokenType
+	Start int
+	End   int
+}
+
+// line based lexer for code highlighting
+type CodeLexer interface {
+	// lex line with state at the end of previous line (0 for the first line),
+	// returns tokens and state at the end of line
+	LexLine(line string, state int) ([]CodeToken, int)
+}
+
+// lexer by language name: go, json, tcl or yaml
+func FindCodeLexer(lang string) CodeLexer {
+	switch strings.ToLower(lang) {
+	case "go", "golang":
+		return NewGoLexer()
+	case "json":
+		return NewJSONLexer()
+	case "tcl":
+		return NewTclLexer()
+	case "yaml", "yml":
+		return NewYAMLLexer()
+	}
+	return nil
+}
+
+func isCodeIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isCodeIdent(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isCodeNumberStart(s []rune, i int) bool {
+	if unicode.IsDigit(s[i]) {
+		return true
+	}
+	return (s[i] == '.' || s[i] == '-') && i+1 < len(s) && unicode.IsDigit(s[i+1])
+}
+
+// scan number from i, returns end offset
+func scanCodeNumber(s []rune, i int) int {
+	i++
+	for i < len(s) && (isCodeIdent(s[i]) || s[i] == '.' ||
+		((s[i] == '+' || s[i] == '-') && (s[i-1] == 'e' || s[i-1] == 'E'))) {
+		i++
+	}
+	return i
+}
+
+// scan quoted text after quote at i-1, returns end offset and closed
+func scanCodeQuoted(s []rune, i int, quote rune, escape bool) (int, bool) {
+	for i < len(s) {
+		if escape && s[i] == '\\' {
+			i += 2
+			continue
+		}
+		if s[i] == quote {
+			return i + 1, true
+		}
+		i++
+	}
+	return len(s), false
+}
+
+func scanCodeIdent(s []rune, i int) int {
+	for i < len(s) && isCodeIdent(s[i]) {
+		i++
+	}
+	return i
+}
+
+// rune offset of sub in s from offset, -1 if not found
+func indexCodeRunes(s []rune, from int, sub string) int {
+	pos := strings.Index(string(s[from:]), sub)
+	if pos == -1 {
+		return -1
+	}
+	return from + len([]rune(string(s[from:])[:pos]))
+}
+
+func makeCodeWordSet(words string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		m[w] = true
+	}
+	return m
+}
+
+type goLexer struct{}
+
+const (
+	goLexerNormal = iota
+	goLexerComment
+	goLexerRawString
+)
+
+var (
+	goKeywords = makeCodeWordSet(`break case chan const continue default defer else fallthrough for func go goto
+		if import interface map package range return select struct switch type var`)
+	goBuiltins = makeCodeWordSet(`append cap clear close complex copy delete imag len make max min new panic print println
+		real recover any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32 int64
+		rune string uint uint8 uint16 uint32 uint64 uintptr true false iota nil`)
+)
+
+func NewGoLexer() CodeLexer {
+	return goLexer{}
+}
+
+func (goLexer) LexLine(line string, state int) (tokens []CodeToken, end int) {
+	s := []rune(line)
+	i := 0
+	switch state {
+	case goLexerComment:
+		pos := indexCodeRunes(s, 0, "*/")
+		if pos == -1 {
+			return []CodeToken{{CodeTokenComment, 0, len(s)}}, goLexerComment
+		}
+		i = pos + 2
+		tokens = append(tokens, CodeToken{CodeTokenComment, 0, i})
+	case goLexerRawString:
+		n, ok := scanCodeQuoted(s, 0, '`', false)
+		tokens = append(tokens, CodeToken{CodeTokenString, 0, n})
+		if !ok {
+			return tokens, goLexerRawString
+		}
+		i = n
+	}
+	for i < len(s) {
+		r := s[i]
+		switch {
+		case r == '/' && i+1 < len(s) && s[i+1] == '/':
+			return append(tokens, CodeToken{CodeTokenComment, i, len(s)}), goLexerNormal
+		case r == '/' && i+1 < len(s) && s[i+1] == '*':
+			pos := indexCodeRunes(s, i+2, "*/")
+			if pos == -1 {
+				return append(tokens, CodeToken{CodeTokenComment, i, len(s)}), goLexerComment
+			}
+			n := pos + 2
+			tokens = append(tokens, CodeToken{CodeTokenComment, i, n})
+			i = n
+		case r == '"' || r == '\'':
+			n, _ := scanCodeQuoted(s, i+1, r, true)
+			tokens = append(tokens, CodeToken{CodeTokenString, i, n})
+			i = n
+		case r == '`':
+			n, ok := scanCodeQuoted(s, i+1, '`', false)
+			tokens = append(tokens, CodeToken{CodeTokenString, i, n})
+			if !ok {
+				return tokens, goLexerRawString
+			}
+			i = n
+		case isCodeNumberStart(s, i) && r != '-':
+			n := scanCodeNumber(s, i)
+			tokens = append(tokens, CodeToken{CodeTokenNumber, i, n})
+			i = n
+		case isCodeIdentStart(r):
+			n := scanCodeIdent(s, i)
+			word := string(s[i:n])
+			if goKeywords[word] {
+				tokens = append(tokens, CodeToken{CodeTokenKeyword, i, n})
+			} else if goBuiltins[word] {
+				tokens = append(tokens, CodeToken{CodeTokenBuiltin, i, n})
+			}
+			i = n
+		default:
+			i++
+		}
+	}
+	return tokens, goLexerNormal
+}
+
+type jsonLexer struct{}
+
+func NewJSONLexer() CodeLexer {
+	return jsonLexer{}
+}
+
+func (jsonLexer) LexLine(line string, state int) (tokens []CodeToken, end int) {
+	s := []rune(line)
+	for i := 0; i < len(s); {
+		r := s[i]
+		switch {
+		case r == '"':
+			n, _ := scanCodeQuoted(s, i+1, '"', true)
+			typ := CodeTokenString
+			j := n
+			for j < len(s) && unicode.IsSpace(s[j]) {
+				j++
+			}
+			if j < len(s) && s[j] == ':' {
+				typ = CodeTokenKey
+			}
+			tokens = append(tokens, CodeToken{typ, i, n})
+			i = n
+		case isCodeNumberStart(s, i):
+			n := scanCodeNumber(s, i)
+			tokens = append(tokens, CodeToken{CodeTokenNumber, i, n})
+			i = n
+		case isCodeIdentStart(r):
+			n := scanCodeIdent(s, i)
+			switch string(s[i:n]) {
+			case "true", "false", "null":
+				tokens = append(tokens, CodeToken{CodeTokenKeyword, i, n})
+			}
+			i = n
+		default:
+			i++
+		}
+	}
+	return tokens, 0
+}
+
+type tclLexer struct{}
+
+const (
+	tclLexerNormal = iota
+	tclLexerString
+)
+
+var (
+	tclKeywords = makeCodeWordSet(`after append array break catch continue default else elseif error eval expr for foreach
+		global if incr info lappend lindex list llength namespace package proc puts rename return set string switch
+		then try unset upvar uplevel variable while`)
+)
+
+func NewTclLexer() CodeLexer {
+	return tclLexer{}
+}
+
+func (tclLexer) LexLine(line string, state int) (tokens []CodeToken, end int) {
+	s := []rune(line)
+	i := 0
+	if state == tclLexerString {
+		n, ok := scanCodeQuoted(s, 0, '"', true)
+		tokens = append(tokens, CodeToken{CodeTokenString, 0, n})
+		if !ok {
+			return tokens, tclLexerString
+		}
+		i = n
+	}
+	// command start for comment and command name
+	cmdStart := i == 0
+	for i < len(s) {
+		r := s[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == ';' || r == '[' || r == '{':
+			cmdStart = true
+			i++
+			continue
+		case r == '#' && cmdStart:
+			return append(tokens, CodeToken{CodeTokenComment, i, len(s)}), tclLexerNormal
+		case r == '"':
+			n, ok := scanCodeQuoted(s, i+1, '"', true)
+			tokens = append(tokens, CodeToken{CodeTokenString, i, n})
+			if !ok {
+				return tokens, tclLexerString
+			}
+			i = n
+		case r == '$':
+			n := i + 1
+			if n < len(s) && s[n] == '{' {
+				n, _ = scanCodeQuoted(s, n+1, '}', false)
+			} else {
+				for n < len(s) && (isCodeIdent(s[n]) || s[n] == ':') {
+					n++
+				}
+			}
+			tokens = append(tokens, CodeToken{CodeTokenVariable, i, n})
+			i = n
+		case isCodeNumberStart(s, i):
+			n := scanCodeNumber(s, i)
+			tokens = append(tokens, CodeToken{CodeTokenNumber, i, n})
+			i = n
+		case isCodeIdentStart(r):
+			n := scanCodeIdent(s, i)
+			if tclKeywords[string(s[i:n])] {
+				tokens = append(tokens, CodeToken{CodeTokenKeyword, i, n})
+			}
+			i = n
+		case r == '\\':
+			i += 2
+		default:
+			i++
+		}
+		cmdStart = false
+	}
+	return tokens, tclLexerNormal
+}
+
+type yamlLexer struct{}
+
+func NewYAMLLexer() CodeLexer {
+	return yamlLexer{}
+}
+
+func (yamlLexer) LexLine(line string, state int) (tokens []CodeToken, end int) {
+	s := []rune(line)
+	i := 0
+	for i < len(s) && unicode.IsSpace(s[i]) {
+		i++
+	}
+	if i+2 < len(s) && string(s[i:i+3]) == "---" || string(s[i:]) == "..." {
+		return []CodeToken{{CodeTokenKeyword, i, len(s)}}, 0
+	}
+	// list item
+	for i+1 < len(s) && s[i] == '-' && s[i+1] == ' ' {
+		i += 2
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+	}
+	// mapping key
+	if i < len(s) && s[i] != '#' {
+		n := i
+		if s[i] == '"' || s[i] == '\'' {
+			n, _ = scanCodeQuoted(s, i+1, s[i], s[i] == '"')
+		} else {
+			for n < len(s) && s[n] != ':' && s[n] != '#' {
+				n++
+			}
+		}
+		if n < len(s) && s[n] == ':' && (n+1 == len(s) || s[n+1] == ' ') {
+			tokens = append(tokens, CodeToken{CodeTokenKey, i, n})
+			i = n + 1
+		}
+	}
+	// value
+	for i < len(s) {
+		r := s[i]
+		switch {
+		case r == '#' && (i == 0 || unicode.IsSpace(s[i-1])):
+			return append(tokens, CodeToken{CodeTokenComment, i, len(s)}), 0
+		case r == '"' || r == '\'':
+			n, _ := scanCodeQuoted(s, i+1, r, r == '"')
+			tokens = append(tokens, CodeToken{CodeTokenString, i, n})
+			i = n
+		case r == '&' || r == '*':
+			n := scanCodeIdent(s, i+1)
+			tokens = append(tokens, CodeToken{CodeTokenVariable, i, n})
+			i = n
+		case isCodeNumberStart(s, i) && (i == 0 || !isCodeIdent(s[i-1])):
+			n := scanCodeNumber(s, i)
+			tokens = append(tokens, CodeToken{CodeTokenNumber, i, n})
+			i = n
+		case isCodeIdentStart(r):
+			n := scanCodeIdent(s, i)
+			switch strings.ToLower(string(s[i:n])) {
+			case "true", "false", "null", "yes", "no", "on", "off":
+				if n == len(s) || unicode.IsSpace(s[n]) || s[n] == ',' {
+					tokens = append(tokens, CodeToken{CodeTokenKeyword, i, n})
+				}
+			}
+			i = n
+		default:
+			i++
+		}
+	}
+	return tokens, 0
+}
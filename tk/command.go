@@ -76,6 +76,7 @@ func bindCommand(id string, command string, fn func()) error {
 	mainInterp.CreateAction(actName, func(args []string) {
 		fn()
 	})
+	addWidgetAction(id, actName)
 	return nil
 }
 
@@ -88,5 +89,6 @@ func bindCommandEx(id string, command string, fn func([]string)) error {
 	mainInterp.CreateAction(actName, func(args []string) {
 		fn(args)
 	})
+	addWidgetAction(id, actName)
 	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	globalContextMenus     = make(map[string]*Binding)
+	globalContextMenusLock sync.Mutex
+)
+
+// mouse events of context menu, aqua uses button 2 (right button) and control-click
+func contextMenuEvents() []string {
+	if eventWindowingSystem() == "aqua" {
+		return []string{"<ButtonPress-2>", "<Control-ButtonPress-1>"}
+	}
+	return []string{"<ButtonPress-3>"}
+}
+
+// popup menu at mouse pointer on context menu click, menu nil for remove
+func SetContextMenu(w Widget, menu *Menu) error {
+	if menu == nil {
+		return SetContextMenuFunc(w, nil)
+	}
+	return SetContextMenuFunc(w, func(e *Event) *Menu {
+		return menu
+	})
+}
+
+// popup menu returned by fn at mouse pointer on context menu click, fn returns nil for no menu
+// and fn nil for remove. the menu is owned by caller and not destroyed after unpost.
+func SetContextMenuFunc(w Widget, fn func(e *Event) *Menu) error {
+	if !IsValidWidget(w) {
+		return ErrInvalid
+	}
+	removeContextMenu(w.Id())
+	if fn == nil {
+		return nil
+	}
+	b := &Binding{tag: w.Id()}
+	for _, event := range contextMenuEvents() {
+		err := b.add(event, func(e *Event) {
+			menu := fn(e)
+			if IsValidWidget(menu) {
+				PopupMenu(menu, e.GlobalPosX, e.GlobalPosY)
+			}
+		})
+		if err != nil {
+			b.Unbind()
+			return err
+		}
+	}
+	globalContextMenusLock.Lock()
+	globalContextMenus[w.Id()] = b
+	globalContextMenusLock.Unlock()
+	return nil
+}
+
+// widget has context menu
+func HasContextMenu(w Widget) bool {
+	if IsNilInterface(w) {
+		return false
+	}
+	globalContextMenusLock.Lock()
+	defer globalContextMenusLock.Unlock()
+	return globalContextMenus[w.Id()] != nil
+}
+
+func removeContextMenu(id string) {
+	globalContextMenusLock.Lock()
+	b := globalContextMenus[id]
+	delete(globalContextMenus, id)
+	globalContextMenusLock.Unlock()
+	if b != nil {
+		b.Unbind()
+	}
+}
+
+// forget context menus of destroyed widget and children, commands are released by releaseWidgetActions
+func releaseContextMenus(id string) {
+	globalContextMenusLock.Lock()
+	defer globalContextMenusLock.Unlock()
+	for k := range globalContextMenus {
+		if id == "." || k == id || strings.HasPrefix(k, id+".") {
+			delete(globalContextMenus, k)
+		}
+	}
+}
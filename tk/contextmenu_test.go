@@ -0,0 +1,61 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	registerTest("ContextMenu", testContextMenu)
+}
+
+func testContextMenu(t *testing.T) {
+	w := NewFrame(nil)
+	menu := NewMenu(w, MenuAttrTearoff(false))
+	bound := func() bool {
+		for _, event := range contextMenuEvents() {
+			if v, _ := evalAsString(fmt.Sprintf("bind %v %v", w.Id(), event)); v == "" {
+				return false
+			}
+		}
+		return true
+	}
+
+	if SetContextMenu(nil, menu) != ErrInvalid {
+		t.Fatal("SetContextMenu", ErrInvalid)
+	}
+	if err := SetContextMenu(w, menu); err != nil {
+		t.Fatal("SetContextMenu", err)
+	}
+	if !HasContextMenu(w) || !bound() {
+		t.Fatal("SetContextMenu", contextMenuEvents())
+	}
+
+	// replace menu, previous binding is removed
+	var events []*Event
+	SetContextMenuFunc(w, func(e *Event) *Menu {
+		events = append(events, e)
+		return nil
+	})
+	if v := widgetActionCount(w.Id()); v != len(contextMenuEvents()) {
+		t.Fatal("SetContextMenuFunc", len(contextMenuEvents()), v)
+	}
+	event := contextMenuEvents()[0]
+	eval(fmt.Sprintf("event generate %v %v -x 5 -y 6", w.Id(), event))
+	if len(events) != 1 || events[0].PosX != 5 || events[0].PosY != 6 {
+		t.Fatal("SetContextMenuFunc", event, events)
+	}
+
+	SetContextMenu(w, nil)
+	if HasContextMenu(w) || bound() || widgetActionCount(w.Id()) != 0 {
+		t.Fatal("SetContextMenu nil", HasContextMenu(w))
+	}
+
+	SetContextMenu(w, menu)
+	w.Destroy()
+	if HasContextMenu(w) {
+		t.Fatal("Destroy", HasContextMenu(w))
+	}
+}
@@ -172,7 +172,7 @@ func (w *Entry) CursorPosition() int {
 }
 
 func (w *Entry) OnUpdate(fn func()) error {
-	return traceVariable(variableId(w.id), fn)
+	return traceVariable(w.id, variableId(w.id), fn)
 }
 
 func (w *Entry) OnEditReturn(fn func()) error {
@@ -177,50 +177,21 @@ type KeyEvent struct {
 	KeyModifier KeyModifier
 }
 
-func (e *KeyEvent) addModifier(sym string, name string, mod KeyModifier) {
-	if strings.HasPrefix(sym, name) {
-		e.KeyModifier |= mod
+// modifier of modifier key keysym
+func keysymModifier(sym string) KeyModifier {
+	switch {
+	case strings.HasPrefix(sym, "Shift_"):
+		return KeyModifierShift
+	case strings.HasPrefix(sym, "Control_"):
+		return KeyModifierControl
+	case strings.HasPrefix(sym, "Alt_"), strings.HasPrefix(sym, "Option_"):
+		return KeyModifierAlt
+	case strings.HasPrefix(sym, "Meta_"), strings.HasPrefix(sym, "Command_"):
+		return KeyModifierMeta
+	case strings.HasPrefix(sym, "Super_"):
+		return KeyModifierFn
 	}
-}
-
-func (e *KeyEvent) removeModifier(sym string, name string, mod KeyModifier) {
-	if strings.HasPrefix(sym, name) {
-		if e.KeyModifier&mod == mod {
-			e.KeyModifier ^= mod
-		}
-	}
-}
-
-// TODO: almost check key modifier
-func BindKeyEventEx(tag string, fnPress func(e *KeyEvent), fnRelease func(e *KeyEvent)) error {
-	var ke KeyEvent
-	var err error
-	err = BindEvent(tag, "<KeyPress>", func(e *Event) {
-		ke.addModifier(e.KeySym, "Shift_", KeyModifierShift)
-		ke.addModifier(e.KeySym, "Control_", KeyModifierControl)
-		ke.addModifier(e.KeySym, "Alt_", KeyModifierAlt)
-		ke.addModifier(e.KeySym, "Meta_", KeyModifierMeta)
-		ke.addModifier(e.KeySym, "Super_", KeyModifierFn)
-		ke.Event = e
-		if fnPress != nil {
-			fnPress(&ke)
-		}
-	})
-	if err != nil {
-		return err
-	}
-	err = BindEvent(tag, "<KeyRelease>", func(e *Event) {
-		ke.Event = e
-		if fnRelease != nil {
-			fnRelease(&ke)
-		}
-		ke.removeModifier(e.KeySym, "Shift_", KeyModifierShift)
-		ke.removeModifier(e.KeySym, "Control_", KeyModifierControl)
-		ke.removeModifier(e.KeySym, "Alt_", KeyModifierAlt)
-		ke.removeModifier(e.KeySym, "Meta_", KeyModifierMeta)
-		ke.removeModifier(e.KeySym, "Super_", KeyModifierFn)
-	})
-	return err
+	return 0
 }
 
 func bindEventHelper(tag string, event string, fnid string, ev *Event, fn func()) error {
@@ -247,18 +218,6 @@ func IsVirtualEvent(event string) bool {
 	return strings.HasPrefix(event, "<<") && strings.HasSuffix(event, ">>")
 }
 
-// add bind event
-func BindEvent(tag string, event string, fn func(e *Event)) error {
-	if tag == "" || !IsEvent(event) {
-		return ErrInvalid
-	}
-	fnid := makeBindEventId()
-	var ev Event
-	return addEventHelper(tag, event, fnid, &ev, func() {
-		fn(&ev)
-	})
-}
-
 // clear tag event
 func ClearBindEvent(tag string, event string) error {
 	if tag == "" || !IsEvent(event) {
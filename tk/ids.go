@@ -110,12 +110,13 @@ func evalGetValue(id string) string {
 	return r
 }
 
-func traceVariable(id string, fn func()) error {
+func traceVariable(owner string, id string, fn func()) error {
 	act := makeActionId()
 	mainInterp.CreateAction(act, func(args []string) {
 		if fn != nil {
 			fn()
 		}
 	})
+	addWidgetAction(owner, act)
 	return eval(fmt.Sprintf("trace add variable %v write %v", id, act))
 }
@@ -5,6 +5,7 @@ package interp
 import (
 	"errors"
 	"strconv"
+	"sync"
 )
 
 const (
@@ -34,63 +35,97 @@ var (
 	globalActionMap  = NewActionMap()
 )
 
+// action registry, safe for concurrent use
 type ActionMap struct {
+	sync.RWMutex
 	fnMap map[uintptr]func([]string)
 	id    uintptr
 }
 
 func NewActionMap() *ActionMap {
-	return &ActionMap{make(map[uintptr]func([]string)), 1}
+	return &ActionMap{fnMap: make(map[uintptr]func([]string)), id: 1}
 }
 
 func (m *ActionMap) Register(fn func([]string)) uintptr {
+	m.Lock()
 	m.id = m.id + 1
-	m.fnMap[m.id] = fn
-	return m.id
+	id := m.id
+	m.fnMap[id] = fn
+	m.Unlock()
+	return id
 }
 
 func (m *ActionMap) UnRegister(id uintptr) {
+	m.Lock()
 	delete(m.fnMap, id)
+	m.Unlock()
+}
+
+func (m *ActionMap) Find(id uintptr) func([]string) {
+	m.RLock()
+	defer m.RUnlock()
+	return m.fnMap[id]
+}
+
+func (m *ActionMap) Len() int {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.fnMap)
 }
 
 func (m *ActionMap) Invoke(id uintptr, args []string) error {
-	fn, ok := m.fnMap[id]
-	if !ok {
+	fn := m.Find(id)
+	if fn == nil {
 		return errors.New("Not found action")
 	}
 	fn(args)
 	return nil
 }
 
+// command registry, safe for concurrent use
 type CommandMap struct {
+	sync.RWMutex
 	fnMap map[uintptr]func([]string) (string, error)
 	id    uintptr
 }
 
 func (m *CommandMap) Register(fn func([]string) (string, error)) uintptr {
+	m.Lock()
 	m.id = m.id + 1
-	m.fnMap[m.id] = fn
-	return m.id
+	id := m.id
+	m.fnMap[id] = fn
+	m.Unlock()
+	return id
 }
 
 func (m *CommandMap) UnRegister(id uintptr) {
+	m.Lock()
 	delete(m.fnMap, id)
+	m.Unlock()
 }
 
 func (m *CommandMap) Find(id uintptr) func([]string) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
 	return m.fnMap[id]
 }
 
+func (m *CommandMap) Len() int {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.fnMap)
+}
+
 func (m *CommandMap) Invoke(id uintptr, args []string) (string, error) {
-	fn, ok := m.fnMap[id]
-	if !ok {
+	fn := m.Find(id)
+	if fn == nil {
 		return "", errors.New("Not found command")
 	}
 	return fn(args)
 }
 
 func NewCommandMap() *CommandMap {
-	return &CommandMap{make(map[uintptr]func([]string) (string, error)), 1}
+	return &CommandMap{fnMap: make(map[uintptr]func([]string) (string, error)), id: 1}
 }
 
 func (interp *Interp) EvalAsString(script string) (string, error) {
@@ -8,6 +8,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -172,6 +173,34 @@ func TestCommand(t *testing.T) {
 	}
 }
 
+func TestRegistryConcurrent(t *testing.T) {
+	m := NewActionMap()
+	cm := NewCommandMap()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := m.Register(func([]string) {})
+				if m.Invoke(id, nil) != nil {
+					t.Error("Invoke action", id)
+				}
+				m.UnRegister(id)
+				cid := cm.Register(func([]string) (string, error) { return "ok", nil })
+				if r, err := cm.Invoke(cid, nil); err != nil || r != "ok" {
+					t.Error("Invoke command", cid, r, err)
+				}
+				cm.UnRegister(cid)
+			}
+		}()
+	}
+	wg.Wait()
+	if m.Len() != 0 || cm.Len() != 0 {
+		t.Fatal("Len", m.Len(), cm.Len())
+	}
+}
+
 func TestObj(t *testing.T) {
 	if NewStringObj("string", interp).ToString() != "string" {
 		t.Fatal("string obj")
@@ -246,6 +275,33 @@ func TestPhoto(t *testing.T) {
 	}
 }
 
+func TestProcessEvents(t *testing.T) {
+	MainLoop(func() {
+		n := 0
+		if !Async(func() {
+			n++
+		}) {
+			t.Fatal("Async")
+		}
+		ProcessEvents()
+		if n != 1 {
+			t.Fatal("ProcessEvents", n)
+		}
+		if !IsMainThread() {
+			t.Fatal("IsMainThread")
+		}
+		if !IsMainLoopRunning() {
+			t.Fatal("IsMainLoopRunning")
+		}
+	})
+	if IsMainLoopRunning() {
+		t.Fatal("IsMainLoopRunning")
+	}
+	if Async(func() {}) {
+		t.Fatal("Async without main loop")
+	}
+}
+
 func TestTkSync(t *testing.T) {
 	MainLoop(func() {
 		go func() {
@@ -12,6 +12,8 @@ import (
 	"image/color"
 	"image/draw"
 	"os"
+	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -72,9 +74,23 @@ static void _c_wrong_num_args(Tcl_Interp *interp, int objc, void *objv, char *me
 import "C"
 
 var (
-	mainLoopThreadId C.Tcl_ThreadId
+	// thread of running main loop, nil if not running
+	mainLoopThread atomic.Pointer[loopThread]
+	// held by Async while posting, MainLoop takes it to close the loop
+	mainLoopLock sync.RWMutex
 )
 
+type loopThread struct {
+	id C.Tcl_ThreadId
+}
+
+func mainLoopThreadId() C.Tcl_ThreadId {
+	if t := mainLoopThread.Load(); t != nil {
+		return t.id
+	}
+	return nil
+}
+
 //export _go_tcl_objcmd_proc
 func _go_tcl_objcmd_proc(clientData unsafe.Pointer, interp *C.Tcl_Interp, objc C.int, objv unsafe.Pointer) C.int {
 	objs := (*(*[1 << 20]*C.Tcl_Obj)(objv))[1:objc:objc]
@@ -126,7 +142,8 @@ func _go_tcl_deleteaction_proc(clientData unsafe.Pointer) {
 
 //export _go_async_event_handler
 func _go_async_event_handler(ev *C.Tcl_Event, flags C.int) C.int {
-	if flags != C.TK_ALL_EVENTS {
+	// accept update and non-blocking event processing
+	if flags|C.TCL_DONT_WAIT != C.TK_ALL_EVENTS|C.TCL_DONT_WAIT {
 		return 0
 	}
 	if fn, ok := globalAsyncEvent.Load(unsafe.Pointer(ev)); ok {
@@ -137,7 +154,13 @@ func _go_async_event_handler(ev *C.Tcl_Event, flags C.int) C.int {
 }
 
 func IsMainThread() bool {
-	return C.Tcl_GetCurrentThread() == mainLoopThreadId
+	tid := mainLoopThreadId()
+	return tid != nil && C.Tcl_GetCurrentThread() == tid
+}
+
+// main loop is running
+func IsMainLoopRunning() bool {
+	return mainLoopThread.Load() != nil
 }
 
 func async_send_event(tid C.Tcl_ThreadId, fn func()) {
@@ -146,20 +169,40 @@ func async_send_event(tid C.Tcl_ThreadId, fn func()) {
 	C._c_send_async_event(tid, ev)
 }
 
-func Async(fn func()) {
+// run fn on main loop thread, returns false and fn is dropped if main loop is not running
+func Async(fn func()) bool {
 	if fn == nil {
-		return
+		return false
+	}
+	mainLoopLock.RLock()
+	defer mainLoopLock.RUnlock()
+	tid := mainLoopThreadId()
+	if tid == nil {
+		return false
+	}
+	async_send_event(tid, fn)
+	return true
+}
+
+// process pending events without waiting, returns number of processed events
+func ProcessEvents() int {
+	n := 0
+	for C.Tcl_DoOneEvent(C.TCL_ALL_EVENTS|C.TCL_DONT_WAIT) != 0 {
+		n++
 	}
-	async_send_event(mainLoopThreadId, fn)
+	return n
 }
 
 func MainLoop(fn func()) {
-	mainLoopThreadId = C.Tcl_GetCurrentThread()
+	mainLoopThread.Store(&loopThread{C.Tcl_GetCurrentThread()})
 	if fn != nil {
 		fn()
 	}
 	C.Tk_MainLoop()
-	mainLoopThreadId = nil
+	// events posted before close are queued, use ProcessEvents to run them
+	mainLoopLock.Lock()
+	mainLoopThread.Store(nil)
+	mainLoopLock.Unlock()
 }
 
 type Interp struct {
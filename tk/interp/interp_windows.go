@@ -11,6 +11,8 @@ import (
 	"image/color"
 	"image/draw"
 	"os"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"unsafe"
 )
@@ -64,6 +66,7 @@ type Tk_PhotoImageBlock struct {
 //sys	Tcl_GetCurrentThread() (threadid *Tcl_ThreadId) = tcl86t.Tcl_GetCurrentThread
 //sys	Tcl_ThreadQueueEvent(threadId *Tcl_ThreadId, evPtr *Tcl_Event, positon Tcl_QueuePosition) = tcl86t.Tcl_ThreadQueueEvent
 //sys	Tcl_ThreadAlert(threadId *Tcl_ThreadId) = tcl86t.Tcl_ThreadAlert
+//sys	Tcl_DoOneEvent(flags int32) (r int32) = tcl86t.Tcl_DoOneEvent
 //sys	Tcl_CreateObjCommand(interp *Tcl_Interp, cmdName *byte, proc uintptr, clientData uintptr, deleteProc uintptr) (cmd *Tcl_Command) = tcl86t.Tcl_CreateObjCommand
 //sys	Tcl_CreateCommand(interp *Tcl_Interp, cmdName *byte, proc uintptr, clientData uintptr, deleteProc uintptr) (cmd *Tcl_Command) = tcl86t.Tcl_CreateCommand
 //sys	Tcl_SetObjResult(interp *Tcl_Interp, resultObjPtr *Tcl_Obj) = tcl86t.Tcl_SetObjResult
@@ -100,11 +103,26 @@ func init() {
 }
 
 var (
-	mainLoopThreadId *Tcl_ThreadId
+	// thread of running main loop, nil if not running
+	mainLoopThread atomic.Pointer[loopThread]
+	// held by Async while posting, MainLoop takes it to close the loop
+	mainLoopLock sync.RWMutex
 )
 
+type loopThread struct {
+	id *Tcl_ThreadId
+}
+
+func mainLoopThreadId() *Tcl_ThreadId {
+	if t := mainLoopThread.Load(); t != nil {
+		return t.id
+	}
+	return nil
+}
+
 func _go_async_event_handler(ev *Tcl_Event, flags int32) int {
-	if flags != TCL_ALL_EVENTS {
+	// accept update and non-blocking event processing
+	if flags|TCL_DONT_WAIT != TCL_ALL_EVENTS|TCL_DONT_WAIT {
 		return 0
 	}
 	if fn, ok := globalAsyncEvent.Load(unsafe.Pointer(ev)); ok {
@@ -115,7 +133,13 @@ func _go_async_event_handler(ev *Tcl_Event, flags int32) int {
 }
 
 func IsMainThread() bool {
-	return Tcl_GetCurrentThread() == mainLoopThreadId
+	tid := mainLoopThreadId()
+	return tid != nil && Tcl_GetCurrentThread() == tid
+}
+
+// main loop is running
+func IsMainLoopRunning() bool {
+	return mainLoopThread.Load() != nil
 }
 
 func async_send_event(tid *Tcl_ThreadId, fn func()) {
@@ -128,20 +152,40 @@ func async_send_event(tid *Tcl_ThreadId, fn func()) {
 	Tcl_ThreadAlert(tid)
 }
 
-func Async(fn func()) {
+// run fn on main loop thread, returns false and fn is dropped if main loop is not running
+func Async(fn func()) bool {
 	if fn == nil {
-		return
+		return false
+	}
+	mainLoopLock.RLock()
+	defer mainLoopLock.RUnlock()
+	tid := mainLoopThreadId()
+	if tid == nil {
+		return false
+	}
+	async_send_event(tid, fn)
+	return true
+}
+
+// process pending events without waiting, returns number of processed events
+func ProcessEvents() int {
+	n := 0
+	for Tcl_DoOneEvent(TCL_ALL_EVENTS|TCL_DONT_WAIT) != 0 {
+		n++
 	}
-	async_send_event(mainLoopThreadId, fn)
+	return n
 }
 
 func MainLoop(fn func()) {
-	mainLoopThreadId = Tcl_GetCurrentThread()
+	mainLoopThread.Store(&loopThread{Tcl_GetCurrentThread()})
 	if fn != nil {
 		fn()
 	}
 	Tk_MainLoop()
-	mainLoopThreadId = nil
+	// events posted before close are queued, use ProcessEvents to run them
+	mainLoopLock.Lock()
+	mainLoopThread.Store(nil)
+	mainLoopLock.Unlock()
 }
 
 type Interp struct {
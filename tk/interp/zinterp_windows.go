@@ -57,6 +57,7 @@ var (
 	procTcl_GetCurrentThread     = modtcl86t.NewProc("Tcl_GetCurrentThread")
 	procTcl_ThreadQueueEvent     = modtcl86t.NewProc("Tcl_ThreadQueueEvent")
 	procTcl_ThreadAlert          = modtcl86t.NewProc("Tcl_ThreadAlert")
+	procTcl_DoOneEvent           = modtcl86t.NewProc("Tcl_DoOneEvent")
 	procTcl_CreateObjCommand     = modtcl86t.NewProc("Tcl_CreateObjCommand")
 	procTcl_CreateCommand        = modtcl86t.NewProc("Tcl_CreateCommand")
 	procTcl_SetObjResult         = modtcl86t.NewProc("Tcl_SetObjResult")
@@ -195,6 +196,12 @@ func Tcl_ThreadAlert(threadId *Tcl_ThreadId) {
 	return
 }
 
+func Tcl_DoOneEvent(flags int32) (r int32) {
+	r0, _, _ := syscall.Syscall(procTcl_DoOneEvent.Addr(), 1, uintptr(flags), 0, 0)
+	r = int32(r0)
+	return
+}
+
 func Tcl_CreateObjCommand(interp *Tcl_Interp, cmdName *byte, proc uintptr, clientData uintptr, deleteProc uintptr) (cmd *Tcl_Command) {
 	r0, _, _ := syscall.Syscall6(procTcl_CreateObjCommand.Addr(), 5, uintptr(unsafe.Pointer(interp)), uintptr(unsafe.Pointer(cmdName)), uintptr(proc), uintptr(clientData), uintptr(deleteProc), 0)
 	cmd = (*Tcl_Command)(unsafe.Pointer(r0))
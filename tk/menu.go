@@ -2,11 +2,15 @@
 
 package tk
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 // menu
 type Menu struct {
 	BaseWidget
+	views []*menuActionView
 }
 
 func NewMenu(parent Widget, attributes ...*WidgetAttr) *Menu {
@@ -196,49 +200,270 @@ func (w *Menu) InsertNewSubMenu(index int, label string, attributes ...*WidgetAt
 	return sub
 }
 
-func (w *Menu) AddActionWithState(act *Action, state string) error {
-	var script string
+// entry type and options of action
+func menuActionScript(act *Action, state string) string {
 	if act.IsSeparator() {
-		script = fmt.Sprintf("%v add separator", w.id)
-	} else if act.IsRadioAction() {
-		setObjText("atk_tmp_label", act.label)
-		script = fmt.Sprintf("%v add radiobutton -label $atk_tmp_label -variable {%v} -value {%v} -command {%v} -state {%v}",
-			w.id, act.groupid, act.radioid, act.actid, state)
+		return "separator"
+	}
+	setObjText("atk_tmp_label", act.label)
+	setObjText("atk_tmp_accelerator", act.shortcut.Text())
+	image := ""
+	if act.icon != nil {
+		image = act.icon.Id()
+	}
+	options := fmt.Sprintf("-label $atk_tmp_label -underline {%v} -command {%v} -state {%v} -accelerator $atk_tmp_accelerator -image {%v} -compound left",
+		act.underline, act.actid, state, image)
+	if act.IsRadioAction() {
+		return fmt.Sprintf("radiobutton %v -variable {%v} -value {%v}", options, act.groupid, act.radioid)
 	} else if act.IsCheckAction() {
-		setObjText("atk_tmp_label", act.label)
-		script = fmt.Sprintf("%v add checkbutton -label $atk_tmp_label -variable {%v} -command {%v} -state {%v}",
-			w.id, act.checkid, act.actid, state)
-	} else {
-		setObjText("atk_tmp_label", act.label)
-		script = fmt.Sprintf("%v add command -label $atk_tmp_label -command {%v} -state {%v}",
-			w.id, act.actid, state)
+		return fmt.Sprintf("checkbutton %v -variable {%v}", options, act.checkid)
 	}
-	return eval(script)
+	return "command " + options
 }
 
+func actionState(act *Action) string {
+	if act.enabled {
+		return "normal"
+	}
+	return "disabled"
+}
+
+// add action with state, the entry state is not changed by action enabled
+func (w *Menu) AddActionWithState(act *Action, state string) error {
+	err := w.insertAction(-1, act, state)
+	if err != nil {
+		return err
+	}
+	if !act.IsSeparator() {
+		view := w.actionView(act)
+		view.fixedState = state
+	}
+	return nil
+}
+
+// add action, the entry follows action label, enabled, visible, icon and shortcut
 func (w *Menu) AddAction(act *Action) error {
-	return w.AddActionWithState(act, "normal")
+	return w.insertAction(-1, act, "")
 }
 
 func (w *Menu) InsertAction(index int, act *Action) error {
+	return w.insertAction(index, act, "")
+}
+
+func (w *Menu) insertAction(index int, act *Action, state string) error {
+	if act == nil {
+		return ErrInvalid
+	}
+	if !act.IsSeparator() {
+		view := w.actionView(act)
+		if !act.visible {
+			if index < 0 {
+				index = menuLastIndex(w.id) + 1
+			}
+			view.hidden = append(view.hidden, view.hiddenEntry(act, index-1, index))
+			return nil
+		}
+	}
+	if state == "" {
+		state = actionState(act)
+	}
 	if index < 0 {
-		return w.AddAction(act)
+		return eval(fmt.Sprintf("%v add %v", w.id, menuActionScript(act, state)))
+	}
+	return eval(fmt.Sprintf("%v insert %v %v", w.id, index, menuActionScript(act, state)))
+}
+
+// menu entries of action
+type menuActionView struct {
+	menu       *Menu
+	act        *Action
+	hidden     []menuHiddenEntry
+	fixedState string
+}
+
+// position of hidden entry by key of next or previous entry and
+// number of entries between, keeps right position after inserts and deletes
+type menuHiddenEntry struct {
+	next       string
+	nextOffset int
+	prev       string
+	prevOffset int
+}
+
+// view of action in menu, created on first use
+func (w *Menu) actionView(act *Action) *menuActionView {
+	for _, v := range act.views {
+		if view, ok := v.(*menuActionView); ok && view.menu == w {
+			return view
+		}
+	}
+	view := &menuActionView{menu: w, act: act}
+	act.addView(view)
+	w.views = append(w.views, view)
+	return view
+}
+
+// stop updating entries of action in menu
+func (w *Menu) detachAction(act *Action) {
+	for _, view := range w.views {
+		if view.act == act {
+			w.detachView(view)
+			return
+		}
+	}
+}
+
+func (w *Menu) detachView(view *menuActionView) {
+	view.act.removeView(view)
+	for n, v := range w.views {
+		if v == view {
+			w.views = append(w.views[:n], w.views[n+1:]...)
+			return
+		}
+	}
+}
+
+func (v *menuActionView) isValid() bool {
+	return IsValidWidget(v.menu)
+}
+
+// index of entries with action command, lookup by command keeps right index after inserts
+func (v *menuActionView) entryIndexes(act *Action) []int {
+	var list []int
+	for i, key := range menuEntryKeys(v.menu.id) {
+		if key == act.actid {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// keys of all entries, command of action entry or menu of cascade entry, empty for others
+func menuEntryKeys(id string) []string {
+	r, _ := evalAsStringList(fmt.Sprintf("set atk_tmp_list {}; set atk_tmp_last [%[1]v index end]; "+
+		"if {[string is integer -strict $atk_tmp_last]} {for {set atk_tmp_i 0} {$atk_tmp_i <= $atk_tmp_last} {incr atk_tmp_i} "+
+		"{set atk_tmp_type [%[1]v type $atk_tmp_i]; if {$atk_tmp_type eq {cascade}} {lappend atk_tmp_list [%[1]v entrycget $atk_tmp_i -menu]} "+
+		"elseif {$atk_tmp_type in {separator tearoff}} {lappend atk_tmp_list {}} else {lappend atk_tmp_list [%[1]v entrycget $atk_tmp_i -command]}}}; set atk_tmp_list", id))
+	return r
+}
+
+// index of first entry with key, -1 for not found
+func menuEntryKeyIndex(keys []string, key string) int {
+	for i, k := range keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// anchors of hidden entry placed between prev and next index, entries of action are skipped
+func (v *menuActionView) hiddenEntry(act *Action, prev int, next int) menuHiddenEntry {
+	keys := menuEntryKeys(v.menu.id)
+	var e menuHiddenEntry
+	for i := next; i < len(keys); i++ {
+		if keys[i] == act.actid {
+			continue
+		}
+		if keys[i] != "" {
+			e.next = keys[i]
+			break
+		}
+		e.nextOffset++
+	}
+	for i := prev; i >= 0; i-- {
+		key := ""
+		if i < len(keys) {
+			key = keys[i]
+		}
+		if key == act.actid {
+			continue
+		}
+		if key != "" {
+			e.prev = key
+			break
+		}
+		e.prevOffset++
+	}
+	return e
+}
+
+// insert index of hidden entry, -1 for end of menu
+func (v *menuActionView) hiddenIndex(e menuHiddenEntry) int {
+	keys := menuEntryKeys(v.menu.id)
+	index := -1
+	if e.next != "" {
+		if i := menuEntryKeyIndex(keys, e.next); i != -1 {
+			index = i - e.nextOffset
+		}
+	}
+	if index == -1 {
+		if e.prev == "" {
+			index = e.prevOffset
+		} else if i := menuEntryKeyIndex(keys, e.prev); i != -1 {
+			index = i + 1 + e.prevOffset
+		} else {
+			return -1
+		}
+	}
+	if index < 0 {
+		return 0
+	} else if index >= len(keys) {
+		return -1
+	}
+	return index
+}
+
+func (v *menuActionView) updateAction(act *Action) {
+	id := v.menu.id
+	if !act.visible {
+		list := v.entryIndexes(act)
+		for _, index := range list {
+			v.hidden = append(v.hidden, v.hiddenEntry(act, index-1, index+1))
+		}
+		for i := len(list) - 1; i >= 0; i-- {
+			eval(fmt.Sprintf("%v delete %v", id, list[i]))
+		}
+		return
+	}
+	state := v.fixedState
+	if state == "" {
+		state = actionState(act)
+	}
+	if len(v.hidden) > 0 {
+		hidden := v.hidden
+		v.hidden = nil
+		for _, e := range hidden {
+			if index := v.hiddenIndex(e); index == -1 {
+				eval(fmt.Sprintf("%v add %v", id, menuActionScript(act, state)))
+			} else {
+				eval(fmt.Sprintf("%v insert %v %v", id, index, menuActionScript(act, state)))
+			}
+		}
 	}
 	setObjText("atk_tmp_label", act.label)
-	var script string
-	if act.IsSeparator() {
-		script = fmt.Sprintf("%v insert %v separator", w.id, index)
-	} else if act.IsRadioAction() {
-		script = fmt.Sprintf("%v insert %v radiobutton -label $atk_tmp_label -variable {%v} -value {%v} -command {%v}",
-			w.id, index, act.groupid, act.radioid, act.actid)
-	} else if act.IsCheckAction() {
-		script = fmt.Sprintf("%v insert %v checkbutton -label $atk_tmp_label -variable {%v} -command {%v}",
-			w.id, index, act.checkid, act.actid)
-	} else {
-		script = fmt.Sprintf("%v insert %v command -label $atk_tmp_label -command {%v}",
-			w.id, index, act.actid)
+	setObjText("atk_tmp_accelerator", act.shortcut.Text())
+	image := ""
+	if act.icon != nil {
+		image = act.icon.Id()
+	}
+	for _, index := range v.entryIndexes(act) {
+		eval(fmt.Sprintf("%v entryconfigure %v -label $atk_tmp_label -underline {%v} -state {%v} -accelerator $atk_tmp_accelerator -image {%v}",
+			id, index, act.underline, state, image))
+	}
+}
+
+// index of last entry, -1 for empty menu
+func menuLastIndex(id string) int {
+	r, err := evalAsString(fmt.Sprintf("%v index end", id))
+	if err != nil {
+		return -1
+	}
+	n, err := strconv.Atoi(r)
+	if err != nil {
+		return -1
 	}
-	return eval(script)
+	return n
 }
 
 func (w *Menu) AddActions(actions []*Action) {
@@ -2,10 +2,15 @@
 
 package tk
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func init() {
 	registerTest("Menu", testMenu)
+	registerTest("MenuAction", testMenuAction)
+	registerTest("MenuEntry", testMenuEntry)
 }
 
 func testMenu(t *testing.T) {
@@ -72,3 +77,211 @@ func testMenu(t *testing.T) {
 		t.Fatal("IsTakeFocus", true, v)
 	}
 }
+
+func testMenuAction(t *testing.T) {
+	w := NewMenu(nil, MenuAttrTearoff(false))
+	defer w.Destroy()
+
+	open := NewAction("Open")
+	save := NewAction("Save")
+	check := NewCheckAction("Wrap")
+	w.AddAction(open)
+	w.AddAction(save)
+	w.AddAction(check)
+	w.InsertAction(0, NewActionEx("New", nil))
+
+	entry := func(index int, option string) string {
+		r, _ := evalAsString(fmt.Sprintf("%v entrycget %v -%v", w.Id(), index, option))
+		return r
+	}
+	save.SetEnabled(false)
+	if v := entry(2, "state"); v != "disabled" {
+		t.Fatal("SetEnabled", "disabled", v)
+	}
+	save.SetLabel("Save As")
+	if v := entry(2, "label"); v != "Save As" {
+		t.Fatal("SetLabel", "Save As", v)
+	}
+	save.SetShortcut(MustParseShortcut("Ctrl+Shift+S"))
+	if v := entry(2, "accelerator"); v != save.Shortcut().Text() {
+		t.Fatal("SetShortcut", save.Shortcut().Text(), v)
+	}
+	save.SetVisible(false)
+	if v := menuLastIndex(w.Id()); v != 2 {
+		t.Fatal("SetVisible", 2, v)
+	}
+	check.SetLabel("Word Wrap")
+	if v := entry(2, "label"); v != "Word Wrap" {
+		t.Fatal("SetLabel", "Word Wrap", v)
+	}
+	// hidden entry keeps position after inserts
+	w.InsertSeparator(0)
+	save.SetVisible(true)
+	if v := entry(3, "label"); v != "Save As" {
+		t.Fatal("SetVisible", "Save As", v)
+	}
+	if v := entry(3, "state"); v != "disabled" {
+		t.Fatal("SetVisible", "disabled", v)
+	}
+	w.DeleteEntries(0, 0)
+
+	changed := 0
+	open.OnChanged(func() {
+		changed++
+	})
+	open.SetTooltip("Open file")
+	if changed != 1 || open.Tooltip() != "Open file" {
+		t.Fatal("OnChanged", changed)
+	}
+
+	// shortcut map follows action shortcut
+	m := NewAppShortcutMap()
+	defer m.Clear()
+	n := 0
+	open.OnCommand(func() {
+		n++
+	})
+	open.SetShortcutText("F2")
+	m.AddAction(open)
+	if !m.Invoke(MustParseShortcut("F2")) || n != 1 {
+		t.Fatal("ShortcutMap.AddAction", n)
+	}
+	open.SetShortcutText("F3")
+	if m.IsConflict(MustParseShortcut("F2")) || !m.IsConflict(MustParseShortcut("F3")) {
+		t.Fatal("ShortcutMap.AddAction", m.Shortcuts())
+	}
+	m.AddText("F4", func() {})
+	if err := open.SetShortcutText("F4"); err != ErrExist || open.Shortcut() != MustParseShortcut("F3") {
+		t.Fatal("SetShortcut conflict", err, open.Shortcut())
+	}
+	open.SetEnabled(false)
+	m.Invoke(MustParseShortcut("F3"))
+	if n != 1 {
+		t.Fatal("Trigger disabled", n)
+	}
+	m.RemoveAction(open)
+	if len(m.Shortcuts()) != 1 {
+		t.Fatal("RemoveAction", m.Shortcuts())
+	}
+	m.AddAction(open)
+	m.Clear()
+	if len(m.Shortcuts()) != 0 || len(open.views) != 1 {
+		t.Fatal("Clear", m.Shortcuts(), open.views)
+	}
+	open.SetShortcutText("F5")
+	if m.IsConflict(MustParseShortcut("F5")) {
+		t.Fatal("Clear", m.Shortcuts())
+	}
+}
+
+func testMenuEntry(t *testing.T) {
+	m := NewMenu(nil, MenuAttrTearoff(false))
+	defer m.Destroy()
+
+	if v := m.EntryCount(); v != 0 {
+		t.Fatal("EntryCount", 0, v)
+	}
+	open := NewAction("")
+	open.SetMnemonicLabel("&Open")
+	m.AddAction(open)
+	m.AddSeparator()
+	wrap := NewCheckAction("Wrap")
+	m.AddAction(wrap)
+	m.AddNewSubMenu("Recent")
+	if v := m.EntryCount(); v != 4 {
+		t.Fatal("EntryCount", 4, v)
+	}
+	for i, typ := range []MenuEntryType{MenuEntryCommand, MenuEntrySeparator, MenuEntryCheckButton, MenuEntryCascade} {
+		if v := m.EntryType(i); v != typ {
+			t.Fatal("EntryType", typ, v)
+		}
+	}
+	if v := m.EntryType(4); v != -1 {
+		t.Fatal("EntryType", -1, v)
+	}
+
+	if v := m.FindEntryByLabel("Open"); v != 0 || open.Underline() != 0 {
+		t.Fatal("FindEntryByLabel", 0, v)
+	}
+	if v := m.FindEntryByLabel("Recent"); v != 3 {
+		t.Fatal("FindEntryByLabel", 3, v)
+	}
+	if v := m.FindEntryByLabel("None"); v != -1 {
+		t.Fatal("FindEntryByLabel", -1, v)
+	}
+	// "&" of action label is literal
+	open.SetLabel("R&D")
+	if v := m.EntryLabel(0); v != "R&D" || open.Underline() != -1 {
+		t.Fatal("SetLabel", "R&D", v, open.Underline())
+	}
+
+	m.EntryConfigure(0, MenuEntryAttrLabel("Open File"), MenuEntryAttrAccelerator("F3"))
+	if v := m.EntryLabel(0); v != "Open File" {
+		t.Fatal("EntryConfigure", "Open File", v)
+	}
+	open.SetLabel("Open")
+	if v := m.EntryLabel(0); v != "Open File" || len(open.views) != 0 {
+		t.Fatal("EntryConfigure detach", "Open File", v)
+	}
+	if m.EntryConfigure(9, MenuEntryAttrLabel("none")) != ErrInvalid {
+		t.Fatal("EntryConfigure", ErrInvalid)
+	}
+
+	m.SetEntryState(0, StateDisable)
+	if v := m.EntryState(0); v != StateDisable {
+		t.Fatal("SetEntryState", StateDisable, v)
+	}
+	n := 0
+	open.OnCommand(func() {
+		n++
+	})
+	m.Invoke(0)
+	if n != 0 {
+		t.Fatal("Invoke disabled", 0, n)
+	}
+	m.SetEntryState(0, StateNormal)
+	m.Invoke(0)
+	if n != 1 {
+		t.Fatal("Invoke", 1, n)
+	}
+
+	var selected []int
+	b, err := m.OnMenuSelect(func(index int) {
+		selected = append(selected, index)
+	})
+	if err != nil {
+		t.Fatal("OnMenuSelect", err)
+	}
+	m.ActivateEntry(2)
+	Update()
+	if v := m.ActiveEntry(); v != 2 {
+		t.Fatal("ActivateEntry", 2, v)
+	}
+	m.ActivateEntry(-1)
+	if v := m.ActiveEntry(); v != -1 {
+		t.Fatal("ActivateEntry", -1, v)
+	}
+	Update()
+	if fmt.Sprint(selected) != "[2 -1]" {
+		t.Fatal("OnMenuSelect", "[2 -1]", selected)
+	}
+	b.Unbind()
+
+	m.PostAt(10, 10)
+	m.Unpost()
+
+	if m.DeleteEntries(2, 1) != ErrInvalid {
+		t.Fatal("DeleteEntries", ErrInvalid)
+	}
+	m.DeleteEntries(1, 2)
+	if v := m.EntryCount(); v != 2 || m.EntryType(1) != MenuEntryCascade {
+		t.Fatal("DeleteEntries", 2, v)
+	}
+	if len(wrap.views) != 0 || len(m.views) != 0 {
+		t.Fatal("DeleteEntries detach", wrap.views)
+	}
+	m.DeleteEntries(0, -1)
+	if v := m.EntryCount(); v != 0 {
+		t.Fatal("DeleteEntries", 0, v)
+	}
+}
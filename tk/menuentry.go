@@ -0,0 +1,250 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+)
+
+type MenuEntryType int
+
+const (
+	MenuEntryCommand MenuEntryType = iota
+	MenuEntryCascade
+	MenuEntryCheckButton
+	MenuEntryRadioButton
+	MenuEntrySeparator
+	MenuEntryTearoff
+)
+
+var (
+	menuEntryTypeName = []string{"command", "cascade", "checkbutton", "radiobutton", "separator", "tearoff"}
+)
+
+func (v MenuEntryType) String() string {
+	if v >= 0 && int(v) < len(menuEntryTypeName) {
+		return menuEntryTypeName[v]
+	}
+	return ""
+}
+
+func parserMenuEntryTypeResult(r string, err error) MenuEntryType {
+	if err != nil {
+		return -1
+	}
+	for n, s := range menuEntryTypeName {
+		if s == r {
+			return MenuEntryType(n)
+		}
+	}
+	return -1
+}
+
+// number of entries, tearoff entry is counted
+func (w *Menu) EntryCount() int {
+	return menuLastIndex(w.id) + 1
+}
+
+func (w *Menu) checkEntryIndex(index int) error {
+	if index < 0 || index > menuLastIndex(w.id) {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// entry type of index, -1 for invalid index
+func (w *Menu) EntryType(index int) MenuEntryType {
+	if w.checkEntryIndex(index) != nil {
+		return -1
+	}
+	return parserMenuEntryTypeResult(evalAsString(fmt.Sprintf("%v type %v", w.id, index)))
+}
+
+// configure entry of index by MenuEntryAttr attributes, entries of action
+// in the menu stop following action changes
+func (w *Menu) EntryConfigure(index int, attributes ...*WidgetAttr) error {
+	if err := w.checkEntryIndex(index); err != nil {
+		return err
+	}
+	script := buildWidgetAttributeScript(nil, false, attributes)
+	if script == "" {
+		return nil
+	}
+	err := eval(fmt.Sprintf("%v entryconfigure %v %v", w.id, index, script))
+	if err != nil {
+		return err
+	}
+	keys := menuEntryKeys(w.id)
+	for _, view := range w.views {
+		if index < len(keys) && view.act.actid == keys[index] {
+			w.detachView(view)
+			break
+		}
+	}
+	return nil
+}
+
+func (w *Menu) EntryLabel(index int) string {
+	if w.checkEntryIndex(index) != nil {
+		return ""
+	}
+	r, _ := evalAsString(fmt.Sprintf("%v entrycget %v -label", w.id, index))
+	return r
+}
+
+func (w *Menu) SetEntryState(index int, state State) error {
+	return w.EntryConfigure(index, MenuEntryAttrState(state))
+}
+
+func (w *Menu) EntryState(index int) State {
+	if w.checkEntryIndex(index) != nil {
+		return StateNormal
+	}
+	return parserStateResult(evalAsString(fmt.Sprintf("%v entrycget %v -state", w.id, index)))
+}
+
+// delete entries from index to index inclusive, to < 0 for last entry,
+// actions without entries left are detached from the menu
+func (w *Menu) DeleteEntries(from int, to int) error {
+	if err := w.checkEntryIndex(from); err != nil {
+		return err
+	}
+	if to < 0 {
+		to = menuLastIndex(w.id)
+	} else if to < from || w.checkEntryIndex(to) != nil {
+		return ErrInvalid
+	}
+	deleted := make(map[string]bool)
+	for _, key := range menuEntryKeys(w.id)[from : to+1] {
+		deleted[key] = true
+	}
+	err := eval(fmt.Sprintf("%v delete %v %v", w.id, from, to))
+	if err != nil {
+		return err
+	}
+	keys := menuEntryKeys(w.id)
+	// hidden actions are detached too when all entries are deleted
+	empty := len(keys) == 0 || (len(keys) == 1 && w.EntryType(0) == MenuEntryTearoff)
+	for _, view := range append([]*menuActionView(nil), w.views...) {
+		if empty || (deleted[view.act.actid] && menuEntryKeyIndex(keys, view.act.actid) == -1) {
+			w.detachView(view)
+		}
+	}
+	return nil
+}
+
+// index of first entry with label, -1 for not found
+func (w *Menu) FindEntryByLabel(label string) int {
+	setObjText("atk_tmp_label", label)
+	r, err := evalAsInt(fmt.Sprintf("set atk_tmp_index -1; set atk_tmp_last [%[1]v index end]; "+
+		"if {[string is integer -strict $atk_tmp_last]} {for {set atk_tmp_i 0} {$atk_tmp_i <= $atk_tmp_last} {incr atk_tmp_i} "+
+		"{if {[%[1]v type $atk_tmp_i] ni {separator tearoff} && [%[1]v entrycget $atk_tmp_i -label] eq $atk_tmp_label} {set atk_tmp_index $atk_tmp_i; break}}}; set atk_tmp_index",
+		w.id))
+	if err != nil {
+		return -1
+	}
+	return r
+}
+
+// invoke entry command of index, check and radio entries toggle variable
+func (w *Menu) Invoke(index int) error {
+	if err := w.checkEntryIndex(index); err != nil {
+		return err
+	}
+	return eval(fmt.Sprintf("%v invoke %v", w.id, index))
+}
+
+// highlight entry of index, -1 for deactivate all entries
+func (w *Menu) ActivateEntry(index int) error {
+	if index < 0 {
+		return eval(fmt.Sprintf("%v activate none", w.id))
+	}
+	if err := w.checkEntryIndex(index); err != nil {
+		return err
+	}
+	return eval(fmt.Sprintf("%v activate %v", w.id, index))
+}
+
+// index of active entry, -1 for none
+func (w *Menu) ActiveEntry() int {
+	return menuActiveIndex(w.id)
+}
+
+func menuActiveIndex(id string) int {
+	r, err := evalAsInt(fmt.Sprintf("set atk_tmp_index [%v index active]; if {![string is integer -strict $atk_tmp_index]} {set atk_tmp_index -1}; set atk_tmp_index", id))
+	if err != nil {
+		return -1
+	}
+	return r
+}
+
+// post menu at screen position, use PopupMenu for popup menu grabbed like tk_popup
+func (w *Menu) PostAt(xpos int, ypos int) error {
+	return eval(fmt.Sprintf("%v post %v %v", w.id, xpos, ypos))
+}
+
+func (w *Menu) Unpost() error {
+	return eval(fmt.Sprintf("%v unpost", w.id))
+}
+
+// bind <<MenuSelect>> for active entry changed, index is -1 for none,
+// fn can show status bar hint of entry
+func (w *Menu) OnMenuSelect(fn func(index int)) (*Binding, error) {
+	if fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: w.id}
+	err := b.addAction("<<MenuSelect>>", "%W", func(args []string) {
+		fn(menuActiveIndex(args[0]))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func MenuEntryAttrLabel(label string) *WidgetAttr {
+	return &WidgetAttr{"label", label}
+}
+
+func MenuEntryAttrUnderline(index int) *WidgetAttr {
+	return &WidgetAttr{"underline", index}
+}
+
+func MenuEntryAttrAccelerator(accelerator string) *WidgetAttr {
+	return &WidgetAttr{"accelerator", accelerator}
+}
+
+func MenuEntryAttrImage(image *Image) *WidgetAttr {
+	if image == nil {
+		return &WidgetAttr{"image", ""}
+	}
+	return &WidgetAttr{"image", image.Id()}
+}
+
+func MenuEntryAttrCompound(compound Compound) *WidgetAttr {
+	return &WidgetAttr{"compound", compound}
+}
+
+func MenuEntryAttrState(state State) *WidgetAttr {
+	return &WidgetAttr{"state", state}
+}
+
+func MenuEntryAttrFont(font Font) *WidgetAttr {
+	if font == nil {
+		return nil
+	}
+	return &WidgetAttr{"font", font.Id()}
+}
+
+func MenuEntryAttrForground(color string) *WidgetAttr {
+	return &WidgetAttr{"foreground", color}
+}
+
+func MenuEntryAttrBackground(color string) *WidgetAttr {
+	return &WidgetAttr{"background", color}
+}
+
+func MenuEntryAttrColumnBreak(columnbreak bool) *WidgetAttr {
+	return &WidgetAttr{"columnbreak", boolToInt(columnbreak)}
+}
@@ -0,0 +1,418 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type MenuSpecType int
+
+const (
+	MenuSpecCommand MenuSpecType = iota
+	MenuSpecCheck
+	MenuSpecRadio
+	MenuSpecSeparator
+	MenuSpecCascade
+)
+
+var (
+	menuSpecTypeName = []string{"command", "check", "radio", "separator", "cascade"}
+)
+
+func (v MenuSpecType) String() string {
+	if v >= 0 && int(v) < len(menuSpecTypeName) {
+		return menuSpecTypeName[v]
+	}
+	return ""
+}
+
+func parserMenuSpecTypeResult(r string, err error) MenuSpecType {
+	if err != nil {
+		return -1
+	}
+	for n, s := range menuSpecTypeName {
+		if s == r {
+			return MenuSpecType(n)
+		}
+	}
+	return -1
+}
+
+func (v MenuSpecType) MarshalText() ([]byte, error) {
+	s := v.String()
+	if s == "" {
+		return nil, ErrInvalid
+	}
+	return []byte(s), nil
+}
+
+func (v *MenuSpecType) UnmarshalText(data []byte) error {
+	t := parserMenuSpecTypeResult(string(data), nil)
+	if t == -1 {
+		return ErrInvalid
+	}
+	*v = t
+	return nil
+}
+
+// special menu and item roles
+const (
+	// menus: apple menu on macOS, help menu, window menu on macOS and system menu on windows
+	MenuRoleApple  = "apple"
+	MenuRoleHelp   = "help"
+	MenuRoleWindow = "window"
+	MenuRoleSystem = "system"
+	// items: moved to apple menu on macOS
+	MenuRoleAbout       = "about"
+	MenuRolePreferences = "preferences"
+	MenuRoleQuit        = "quit"
+)
+
+// declarative menu item, Label may have mnemonic like "&File",
+// Shortcut is parsed by ParseShortcut and bound to window,
+// Name is used to find action or sub menu and bind command after load
+type MenuSpec struct {
+	Label    string       `json:"label,omitempty"`
+	Type     MenuSpecType `json:"type,omitempty"`
+	Name     string       `json:"name,omitempty"`
+	Shortcut string       `json:"shortcut,omitempty"`
+	Tooltip  string       `json:"tooltip,omitempty"`
+	Group    string       `json:"group,omitempty"`
+	Checked  bool         `json:"checked,omitempty"`
+	Disabled bool         `json:"disabled,omitempty"`
+	Role     string       `json:"role,omitempty"`
+	Items    []*MenuSpec  `json:"items,omitempty"`
+	// use action instead of creating new one
+	Action *Action `json:"-"`
+	Icon   *Image  `json:"-"`
+	// item command
+	Command func() `json:"-"`
+	// sub menu post command, for dynamic menus like recent files
+	OnPost func(menu *Menu) `json:"-"`
+}
+
+func (s *MenuSpec) itemType() MenuSpecType {
+	if s.Type == MenuSpecCommand && (len(s.Items) > 0 || s.OnPost != nil) {
+		return MenuSpecCascade
+	}
+	if s.Type == MenuSpecCommand && s.Label == "-" {
+		return MenuSpecSeparator
+	}
+	return s.Type
+}
+
+// parse menu specs from json array
+func ParseMenuSpecJSON(data []byte) ([]*MenuSpec, error) {
+	var specs []*MenuSpec
+	err := json.Unmarshal(data, &specs)
+	if err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// menubar built from menu specs
+type MenuBar struct {
+	window    *Window
+	menu      *Menu
+	shortcuts *ShortcutMap
+	actions   map[string]*Action
+	menus     map[string]*Menu
+	groups    map[string]*ActionGroup
+	bound     []*Action
+	aqua      bool
+}
+
+// build menubar from specs and set as window menu, returns ErrExist error
+// for duplicate shortcuts
+func NewMenuBar(w *Window, specs []*MenuSpec) (*MenuBar, error) {
+	if !IsValidWidget(w) {
+		return nil, ErrInvalid
+	}
+	err := checkMenuSpecShortcuts(specs, make(map[string]string))
+	if err != nil {
+		return nil, err
+	}
+	b := &MenuBar{
+		window:    w,
+		shortcuts: NewWindowShortcutMap(w),
+		actions:   make(map[string]*Action),
+		menus:     make(map[string]*Menu),
+		groups:    make(map[string]*ActionGroup),
+		aqua:      WindowingSystem() == "aqua",
+	}
+	b.menu = NewMenu(w, MenuAttrTearoff(false))
+	if b.menu == nil {
+		return nil, ErrInvalid
+	}
+	// about, preferences and quit items
+	var apple *MenuSpec
+	if b.aqua {
+		apple, specs = moveAppleMenuItems(specs)
+	}
+	if apple != nil {
+		err = b.addCascade(b.menu, apple)
+	}
+	for _, spec := range specs {
+		if err != nil {
+			break
+		}
+		if spec.Role == MenuRoleApple {
+			continue
+		}
+		err = b.addSpec(b.menu, spec)
+	}
+	if err != nil {
+		b.Destroy()
+		return nil, err
+	}
+	err = w.SetMenu(b.menu)
+	if err != nil {
+		b.Destroy()
+		return nil, err
+	}
+	return b, nil
+}
+
+func checkMenuSpecShortcuts(specs []*MenuSpec, used map[string]string) error {
+	for _, spec := range specs {
+		if spec.Shortcut != "" {
+			sc, err := ParseShortcut(spec.Shortcut)
+			if err != nil {
+				return fmt.Errorf("menu %q shortcut %q: %w", spec.Label, spec.Shortcut, err)
+			}
+			if label, ok := used[sc.Sequence()]; ok {
+				return fmt.Errorf("menu %q shortcut %q used by %q: %w", spec.Label, spec.Shortcut, label, ErrExist)
+			}
+			used[sc.Sequence()] = spec.Label
+		}
+		err := checkMenuSpecShortcuts(spec.Items, used)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect apple menu and about, preferences, quit items for macOS application menu
+func moveAppleMenuItems(specs []*MenuSpec) (*MenuSpec, []*MenuSpec) {
+	apple := &MenuSpec{Role: MenuRoleApple}
+	for _, spec := range specs {
+		if spec.Role == MenuRoleApple {
+			apple.Label = spec.Label
+			apple.Items = append(apple.Items, spec.Items...)
+		}
+	}
+	var about []*MenuSpec
+	var move func(items []*MenuSpec) []*MenuSpec
+	move = func(items []*MenuSpec) []*MenuSpec {
+		var list []*MenuSpec
+		for _, item := range items {
+			switch item.Role {
+			case MenuRoleAbout:
+				about = append(about, item)
+			case MenuRolePreferences, MenuRoleQuit:
+				// bound to tk::mac procs, separator before item is removed too
+				apple.Items = append(apple.Items, item)
+				if n := len(list); n > 0 && list[n-1].itemType() == MenuSpecSeparator {
+					list = list[:n-1]
+				}
+			default:
+				if item.Role != MenuRoleApple {
+					item.Items = move(item.Items)
+				}
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+	specs = move(specs)
+	apple.Items = append(about, apple.Items...)
+	if len(apple.Items) == 0 {
+		return nil, specs
+	}
+	return apple, specs
+}
+
+func (b *MenuBar) addSpec(menu *Menu, spec *MenuSpec) error {
+	switch spec.itemType() {
+	case MenuSpecSeparator:
+		return menu.AddSeparator()
+	case MenuSpecCascade:
+		return b.addCascade(menu, spec)
+	}
+	act, err := b.specAction(spec)
+	if err != nil {
+		return err
+	}
+	if b.aqua && (spec.Role == MenuRolePreferences || spec.Role == MenuRoleQuit) {
+		proc := "::tk::mac::ShowPreferences"
+		if spec.Role == MenuRoleQuit {
+			proc = "::tk::mac::Quit"
+		}
+		return eval(fmt.Sprintf("proc %v {} {%v}", proc, act.actid))
+	}
+	err = menu.AddAction(act)
+	if err != nil {
+		return err
+	}
+	if act.shortcut.IsValid() {
+		err = b.shortcuts.AddAction(act)
+		if err != nil {
+			return fmt.Errorf("menu %q shortcut %q: %w", spec.Label, act.shortcut.String(), err)
+		}
+		b.bound = append(b.bound, act)
+	}
+	return nil
+}
+
+// special sub menu path name of role
+func (b *MenuBar) roleMenuName(role string) string {
+	switch role {
+	case MenuRoleApple, MenuRoleWindow:
+		if b.aqua {
+			return role
+		}
+	case MenuRoleHelp:
+		return role
+	case MenuRoleSystem:
+		if WindowingSystem() == "win32" {
+			return role
+		}
+	}
+	return ""
+}
+
+func (b *MenuBar) addCascade(menu *Menu, spec *MenuSpec) error {
+	var sub *Menu
+	if name := b.roleMenuName(spec.Role); name != "" && menu == b.menu {
+		id := b.menu.Id() + "." + name
+		err := eval(fmt.Sprintf("menu %v -tearoff 0", id))
+		if err != nil {
+			return err
+		}
+		sub = &Menu{}
+		err = sub.Attach(id)
+		if err != nil {
+			return err
+		}
+	} else {
+		sub = NewMenu(menu, MenuAttrTearoff(false))
+		if sub == nil {
+			return ErrInvalid
+		}
+	}
+	if spec.Name != "" {
+		b.menus[spec.Name] = sub
+	}
+	if spec.OnPost != nil {
+		fn := spec.OnPost
+		act := makeActionId()
+		mainInterp.CreateAction(act, func([]string) {
+			fn(sub)
+		})
+		addWidgetAction(sub.Id(), act)
+		eval(fmt.Sprintf("%v configure -postcommand {%v}", sub.Id(), act))
+	}
+	for _, item := range spec.Items {
+		err := b.addSpec(sub, item)
+		if err != nil {
+			return err
+		}
+	}
+	label, underline := splitMnemonic(spec.Label)
+	setObjText("atk_tmp_label", label)
+	return eval(fmt.Sprintf("%v add cascade -label $atk_tmp_label -underline {%v} -menu {%v}",
+		menu.Id(), underline, sub.Id()))
+}
+
+func (b *MenuBar) specAction(spec *MenuSpec) (*Action, error) {
+	act := spec.Action
+	if act == nil {
+		switch spec.itemType() {
+		case MenuSpecCheck:
+			act = NewCheckAction("")
+			act.SetChecked(spec.Checked)
+		case MenuSpecRadio:
+			group := b.groups[spec.Group]
+			if group == nil {
+				group = NewActionGroup()
+				b.groups[spec.Group] = group
+			}
+			act = group.AddNewRadioAction("")
+			if spec.Checked {
+				act.SetChecked(true)
+			}
+		default:
+			act = NewAction("")
+		}
+		act.SetMnemonicLabel(spec.Label)
+		act.SetEnabled(!spec.Disabled)
+		act.SetTooltip(spec.Tooltip)
+		act.SetIcon(spec.Icon)
+	}
+	if spec.Shortcut != "" {
+		err := act.SetShortcutText(spec.Shortcut)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if spec.Command != nil {
+		act.OnCommand(spec.Command)
+	}
+	if spec.Name != "" {
+		b.actions[spec.Name] = act
+	}
+	return act, nil
+}
+
+func (b *MenuBar) Menu() *Menu {
+	return b.menu
+}
+
+func (b *MenuBar) Window() *Window {
+	return b.window
+}
+
+// shortcuts bound to window
+func (b *MenuBar) Shortcuts() *ShortcutMap {
+	return b.shortcuts
+}
+
+// action of item name
+func (b *MenuBar) Action(name string) *Action {
+	return b.actions[name]
+}
+
+// sub menu of cascade item name
+func (b *MenuBar) SubMenu(name string) *Menu {
+	return b.menus[name]
+}
+
+// radio action group of group name
+func (b *MenuBar) ActionGroup(group string) *ActionGroup {
+	return b.groups[group]
+}
+
+// bind command to action of item name
+func (b *MenuBar) OnCommand(name string, fn func()) error {
+	act := b.actions[name]
+	if act == nil {
+		return ErrNotExist
+	}
+	return act.OnCommand(fn)
+}
+
+// remove menubar from window and destroy menus
+func (b *MenuBar) Destroy() error {
+	for _, act := range b.bound {
+		b.shortcuts.RemoveAction(act)
+	}
+	b.bound = nil
+	if IsValidWidget(b.window) && b.window.Menu() == b.menu {
+		b.window.SetMenu(nil)
+	}
+	return b.menu.Destroy()
+}
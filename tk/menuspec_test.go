@@ -0,0 +1,142 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func init() {
+	registerTest("MenuSpec", testMenuSpec)
+}
+
+func testMenuSpec(t *testing.T) {
+	for _, v := range []struct {
+		label     string
+		text      string
+		underline int
+	}{
+		{"&File", "File", 0},
+		{"Save &As", "Save As", 5},
+		{"Fish && Chips", "Fish & Chips", -1},
+		{"A & B", "A & B", -1},
+		{"End&", "End&", -1},
+	} {
+		text, underline := splitMnemonic(v.label)
+		if text != v.text || underline != v.underline {
+			t.Fatal("splitMnemonic", v, text, underline)
+		}
+	}
+
+	specs, err := ParseMenuSpecJSON([]byte(`[
+	{"label": "&File", "items": [
+		{"label": "&Open", "name": "open", "shortcut": "Ctrl+O"},
+		{"label": "-"},
+		{"label": "&Wrap", "name": "wrap", "type": "check", "checked": true},
+		{"label": "Recent", "name": "recent", "type": "cascade"}
+	]},
+	{"label": "&Help", "role": "help", "items": [
+		{"label": "&About", "name": "about", "role": "about", "disabled": true}
+	]}
+]`))
+	if err != nil {
+		t.Fatal("ParseMenuSpecJSON", err)
+	}
+	if specs[0].Items[1].itemType() != MenuSpecSeparator || specs[0].Items[2].Type != MenuSpecCheck {
+		t.Fatal("ParseMenuSpecJSON", specs[0].Items[1].itemType(), specs[0].Items[2].Type)
+	}
+	if _, err := ParseMenuSpecJSON([]byte(`[{"type": "button"}]`)); err == nil {
+		t.Fatal("ParseMenuSpecJSON", "invalid type")
+	}
+
+	posted := 0
+	specs[0].Items[3].OnPost = func(m *Menu) {
+		posted++
+		m.AddAction(NewAction(fmt.Sprintf("file%v", posted)))
+	}
+	w := NewWindow()
+	defer w.Destroy()
+	bar, err := NewMenuBar(w, specs)
+	if err != nil {
+		t.Fatal("NewMenuBar", err)
+	}
+	if w.Menu() != bar.Menu() {
+		t.Fatal("SetMenu", bar.Menu(), w.Menu())
+	}
+	if v, _ := evalAsString(fmt.Sprintf("%v entrycget 0 -label", bar.Menu().Id())); v != "File" {
+		t.Fatal("cascade label", "File", v)
+	}
+	if v, _ := evalAsInt(fmt.Sprintf("%v entrycget 0 -underline", bar.Menu().Id())); v != 0 {
+		t.Fatal("cascade underline", 0, v)
+	}
+
+	open := bar.Action("open")
+	if open == nil || open.Shortcut() != MustParseShortcut("Ctrl+O") {
+		t.Fatal("Action", open)
+	}
+	if open.Label() != "Open" || open.Underline() != 0 {
+		t.Fatal("Action label", open.Label(), open.Underline())
+	}
+	file, _ := evalAsString(fmt.Sprintf("%v entrycget 0 -menu", bar.Menu().Id()))
+	if v, _ := evalAsString(fmt.Sprintf("%v entrycget 0 -accelerator", file)); v != open.Shortcut().Text() {
+		t.Fatal("accelerator", open.Shortcut().Text(), v)
+	}
+	opened := 0
+	bar.OnCommand("open", func() {
+		opened++
+	})
+	if !bar.Shortcuts().Invoke(open.Shortcut()) || opened != 1 {
+		t.Fatal("Shortcuts", opened)
+	}
+	if !bar.Action("wrap").IsChecked() {
+		t.Fatal("Checked", false)
+	}
+	if bar.OnCommand("none", func() {}) != ErrNotExist {
+		t.Fatal("OnCommand", ErrNotExist)
+	}
+
+	recent := bar.SubMenu("recent")
+	if recent == nil {
+		t.Fatal("SubMenu", "recent")
+	}
+	eval(fmt.Sprintf("%v postcascade 3", file))
+	eval(fmt.Sprintf("%v unpost", recent.Id()))
+	if posted == 0 {
+		t.Fatal("OnPost", posted)
+	}
+
+	if WindowingSystem() != "aqua" {
+		if bar.Action("about").IsEnabled() {
+			t.Fatal("Disabled", true)
+		}
+		help, _ := evalAsString(fmt.Sprintf("%v entrycget 1 -menu", bar.Menu().Id()))
+		if help != bar.Menu().Id()+".help" {
+			t.Fatal("help menu", bar.Menu().Id()+".help", help)
+		}
+	}
+
+	bar.Destroy()
+	if w.Menu() != nil || bar.Shortcuts().IsConflict(open.Shortcut()) {
+		t.Fatal("Destroy", w.Menu())
+	}
+
+	_, err = NewMenuBar(w, []*MenuSpec{
+		{Label: "Open", Shortcut: "Ctrl+O"},
+		{Label: "Edit", Items: []*MenuSpec{{Label: "Other", Shortcut: "ctrl+o"}}},
+	})
+	if !errors.Is(err, ErrExist) {
+		t.Fatal("NewMenuBar", ErrExist, err)
+	}
+	// shortcut of spec action is checked when bound
+	act := NewAction("New")
+	act.SetShortcutText("Ctrl+N")
+	_, err = NewMenuBar(w, []*MenuSpec{
+		{Label: "New", Action: act},
+		{Label: "Other", Shortcut: "Ctrl+N"},
+	})
+	if !errors.Is(err, ErrExist) || w.Menu() != nil {
+		t.Fatal("NewMenuBar action", ErrExist, err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"sync"
+)
+
+const (
+	receiverMaxBatch = 256
+)
+
+// channel subscription created by OnReceive
+type Receiver struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+// stop receive, values not yet delivered are dropped. safe to call from any goroutine.
+func (r *Receiver) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (r *Receiver) IsStopped() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop receive when widget is destroyed, call on main thread
+func (r *Receiver) StopOnDestroy(w Widget) error {
+	if !IsValidWidget(w) {
+		return ErrInvalid
+	}
+	id := w.Id()
+	_, err := BindEvent(id, "<Destroy>", func(e *Event) {
+		if e.Widget != nil && e.Widget.Id() == id {
+			r.Stop()
+		}
+	})
+	return err
+}
+
+// receive values from ch on main thread, fn is called for each value.
+// see OnReceiveBatch.
+func OnReceive[T any](ch <-chan T, fn func(T)) *Receiver {
+	if fn == nil {
+		return nil
+	}
+	return OnReceiveBatch(ch, func(values []T) {
+		for _, v := range values {
+			fn(v)
+		}
+	})
+}
+
+// receive values from ch on main thread in batches, one batch per event loop iteration.
+// The next batch is not read until fn returns, so a busy main thread blocks the senders.
+// Receive stops when ch is closed or Stop is called.
+func OnReceiveBatch[T any](ch <-chan T, fn func([]T)) *Receiver {
+	if ch == nil || fn == nil {
+		return nil
+	}
+	r := &Receiver{stop: make(chan struct{})}
+	go func() {
+		done := make(chan struct{}, 1)
+		for {
+			var batch []T
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					r.Stop()
+					return
+				}
+				batch = append(batch, v)
+			case <-r.stop:
+				return
+			}
+			closed := false
+		drain:
+			for len(batch) < receiverMaxBatch {
+				select {
+				case v, ok := <-ch:
+					if !ok {
+						closed = true
+						break drain
+					}
+					batch = append(batch, v)
+				default:
+					break drain
+				}
+			}
+			err := Async(func() {
+				if !r.IsStopped() {
+					fn(batch)
+				}
+				done <- struct{}{}
+			})
+			if err != nil {
+				// main loop is not running
+				r.Stop()
+				return
+			}
+			select {
+			case <-done:
+			case <-r.stop:
+				return
+			}
+			if closed {
+				r.Stop()
+				return
+			}
+		}
+	}()
+	return r
+}
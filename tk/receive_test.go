@@ -0,0 +1,43 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"testing"
+	"time"
+)
+
+func init() {
+	registerTest("Receive", testReceive)
+}
+
+func testReceive(t *testing.T) {
+	ch := make(chan int, 10)
+	for i := 1; i <= 5; i++ {
+		ch <- i
+	}
+	close(ch)
+	var values []int
+	batches := 0
+	r := OnReceiveBatch(ch, func(v []int) {
+		batches++
+		values = append(values, v...)
+	})
+	for start := time.Now(); !r.IsStopped() && time.Since(start) < time.Second; {
+		Update()
+	}
+	if len(values) != 5 || values[4] != 5 || batches != 1 {
+		t.Fatal("OnReceiveBatch", values, batches)
+	}
+
+	w := NewLabel(nil, "")
+	ch2 := make(chan string)
+	r = OnReceive(ch2, func(s string) {
+		w.SetText(s)
+	})
+	r.StopOnDestroy(w)
+	w.Destroy()
+	if !r.IsStopped() {
+		t.Fatal("StopOnDestroy")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"context"
+	"errors"
+	"runtime"
+
+	"github.com/visualfc/atk/tk/interp"
+)
+
+var (
+	shutdownHooks []func() error
+	shutdownDone  bool
+	shutdownError error
+	exitError     error
+)
+
+// add hook called on shutdown, hooks run in reverse order of adding.
+// Quit, QuitWithError and Run context cancel run hooks before the root window is destroyed,
+// if the last window is closed by user hooks run after the main loop ends.
+// Hooks are removed when Run returns.
+func AddShutdownHook(fn func() error) {
+	if fn == nil {
+		return
+	}
+	shutdownHooks = append(shutdownHooks, fn)
+}
+
+func runShutdownHooks() {
+	if shutdownDone {
+		return
+	}
+	shutdownDone = true
+	var errs []error
+	for i := len(shutdownHooks) - 1; i >= 0; i-- {
+		if err := shutdownHooks[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	shutdownError = errors.Join(errs...)
+}
+
+func shutdown(err error) {
+	if exitError == nil {
+		exitError = err
+	}
+	runShutdownHooks()
+	DestroyWidget(rootWindow)
+}
+
+// reset shutdown state of previous Run
+func resetShutdown() {
+	shutdownDone = false
+	shutdownError = nil
+	exitError = nil
+}
+
+// run hooks not run yet and remove hooks, returns exit error joined with hook errors and err
+func finishShutdown(err error) error {
+	runShutdownHooks()
+	shutdownHooks = nil
+	return errors.Join(exitError, shutdownError, err)
+}
+
+// quit main loop, Run returns err
+func QuitWithError(err error) {
+	Async(func() {
+		shutdown(err)
+	})
+}
+
+// run main loop until ctx is done or the last window is closed, fn is called on main thread
+// before loop. After loop Run runs shutdown hooks, processes queued async events and deletes
+// the interpreter. Returns ctx error, QuitWithError error, shutdown hook errors or init error.
+func Run(ctx context.Context, fn func()) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if !tkHasInit {
+		err := Init()
+		if err != nil {
+			return err
+		}
+	}
+	resetShutdown()
+	stop := make(chan struct{})
+	interp.MainLoop(func() {
+		go func() {
+			select {
+			case <-ctx.Done():
+				QuitWithError(ctx.Err())
+			case <-stop:
+			}
+		}()
+		if fn != nil {
+			fn()
+		}
+	})
+	// loop is closed and Async returns ErrClosed, process events posted before until queue is empty
+	close(stop)
+	runShutdownHooks()
+	interp.ProcessEvents()
+	removeWidget(".")
+	err := mainInterp.Destroy()
+	tkHasInit = false
+	return finishShutdown(err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	// context cancel runs hooks and drains async calls
+	var order []int
+	errHook := errors.New("hook")
+	ctx, cancel := context.WithCancel(context.Background())
+	waited := make(chan error, 1)
+	err := Run(ctx, func() {
+		AddShutdownHook(nil)
+		AddShutdownHook(func() error {
+			order = append(order, 1)
+			return nil
+		})
+		AddShutdownHook(func() error {
+			order = append(order, 2)
+			return errHook
+		})
+		go func() {
+			for {
+				if err := AsyncWait(context.Background(), func() {}); err != nil {
+					waited <- err
+					return
+				}
+			}
+		}()
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errHook) || fmt.Sprint(order) != "[2 1]" {
+		t.Fatal("Run cancel", err, order)
+	}
+	select {
+	case err := <-waited:
+		if err != ErrClosed {
+			t.Fatal("AsyncWait", ErrClosed, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AsyncWait blocked after Run")
+	}
+	if Async(func() {}) != ErrClosed {
+		t.Fatal("Async", ErrClosed)
+	}
+
+	// second run after destroy starts without hooks and error of previous run
+	errQuit := errors.New("quit")
+	err = Run(context.Background(), func() {
+		QuitWithError(errQuit)
+		QuitWithError(errors.New("second"))
+	})
+	if !errors.Is(err, errQuit) || errors.Is(err, errHook) || len(order) != 2 {
+		t.Fatal("Run QuitWithError", err, order)
+	}
+
+	// last window closed
+	closed := false
+	err = Run(context.Background(), func() {
+		AddShutdownHook(func() error {
+			closed = true
+			return nil
+		})
+		go Async(func() {
+			DestroyWidget(RootWindow())
+		})
+	})
+	if err != nil || !closed {
+		t.Fatal("Run window closed", err, closed)
+	}
+}
@@ -0,0 +1,504 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// keyboard shortcut, Key is tk keysym
+type Shortcut struct {
+	Modifier KeyModifier
+	Key      string
+}
+
+var (
+	shortcutModifierNames = map[string]KeyModifier{
+		"ctrl":    KeyModifierControl,
+		"control": KeyModifierControl,
+		"shift":   KeyModifierShift,
+		"alt":     KeyModifierAlt,
+		"option":  KeyModifierAlt,
+		"opt":     KeyModifierAlt,
+		"meta":    KeyModifierMeta,
+		"super":   KeyModifierMeta,
+		"win":     KeyModifierMeta,
+	}
+	shortcutKeyNames = map[string]string{
+		"enter":     "Return",
+		"return":    "Return",
+		"esc":       "Escape",
+		"escape":    "Escape",
+		"del":       "Delete",
+		"delete":    "Delete",
+		"backspace": "BackSpace",
+		"tab":       "Tab",
+		"space":     "space",
+		"up":        "Up",
+		"down":      "Down",
+		"left":      "Left",
+		"right":     "Right",
+		"home":      "Home",
+		"end":       "End",
+		"pgup":      "Prior",
+		"pageup":    "Prior",
+		"prior":     "Prior",
+		"pgdown":    "Next",
+		"pagedown":  "Next",
+		"next":      "Next",
+		"ins":       "Insert",
+		"insert":    "Insert",
+		"+":         "plus",
+		"plus":      "plus",
+		"-":         "minus",
+		"minus":     "minus",
+		"=":         "equal",
+		",":         "comma",
+		".":         "period",
+		"/":         "slash",
+		"\\":        "backslash",
+		";":         "semicolon",
+		"'":         "apostrophe",
+		"`":         "grave",
+		"[":         "bracketleft",
+		"]":         "bracketright",
+	}
+	shortcutKeyText = map[string]string{
+		"Return":       "Enter",
+		"Escape":       "Esc",
+		"Delete":       "Del",
+		"BackSpace":    "Backspace",
+		"space":        "Space",
+		"Prior":        "PgUp",
+		"Next":         "PgDown",
+		"Insert":       "Ins",
+		"plus":         "+",
+		"minus":        "-",
+		"equal":        "=",
+		"comma":        ",",
+		"period":       ".",
+		"slash":        "/",
+		"backslash":    "\\",
+		"semicolon":    ";",
+		"apostrophe":   "'",
+		"grave":        "`",
+		"bracketleft":  "[",
+		"bracketright": "]",
+	}
+	// keysyms of shifted digit and punctuation keys on us keyboard layout
+	shortcutShiftedKeys = map[string]string{
+		"1":            "exclam",
+		"2":            "at",
+		"3":            "numbersign",
+		"4":            "dollar",
+		"5":            "percent",
+		"6":            "asciicircum",
+		"7":            "ampersand",
+		"8":            "asterisk",
+		"9":            "parenleft",
+		"0":            "parenright",
+		"minus":        "underscore",
+		"equal":        "plus",
+		"comma":        "less",
+		"period":       "greater",
+		"slash":        "question",
+		"backslash":    "bar",
+		"semicolon":    "colon",
+		"apostrophe":   "quotedbl",
+		"grave":        "asciitilde",
+		"bracketleft":  "braceleft",
+		"bracketright": "braceright",
+	}
+)
+
+// shortcuts use macOS conventions
+func isAquaShortcut() bool {
+	if ws := eventWindowingSystem(); ws != "" {
+		return ws == "aqua"
+	}
+	return runtime.GOOS == "darwin"
+}
+
+// parse shortcut from text like "Ctrl+Shift+S", "Alt+F4" or "Cmd+O",
+// Cmd (Command, Primary, CmdOrCtrl) is Command key on macOS and Ctrl on others
+func ParseShortcut(text string) (Shortcut, error) {
+	var sc Shortcut
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return sc, ErrInvalid
+	}
+	var parts []string
+	if strings.HasSuffix(text, "++") {
+		parts = append(strings.Split(text[:len(text)-2], "+"), "+")
+	} else if text == "+" {
+		parts = []string{"+"}
+	} else {
+		parts = strings.Split(text, "+")
+	}
+	for n, part := range parts {
+		part = strings.TrimSpace(part)
+		if n == len(parts)-1 {
+			sc.Key = shortcutKeysym(part)
+			break
+		}
+		switch name := strings.ToLower(part); name {
+		case "cmd", "command", "primary", "cmdorctrl", "mod":
+			if isAquaShortcut() {
+				sc.Modifier |= KeyModifierMeta
+			} else {
+				sc.Modifier |= KeyModifierControl
+			}
+		default:
+			mod, ok := shortcutModifierNames[name]
+			if !ok {
+				return Shortcut{}, ErrInvalid
+			}
+			sc.Modifier |= mod
+		}
+	}
+	if sc.Key == "" {
+		return Shortcut{}, ErrInvalid
+	}
+	return sc, nil
+}
+
+// parse shortcut, panics if text is invalid
+func MustParseShortcut(text string) Shortcut {
+	sc, err := ParseShortcut(text)
+	if err != nil {
+		panic(fmt.Errorf("invalid shortcut %q", text))
+	}
+	return sc
+}
+
+func shortcutKeysym(key string) string {
+	if key == "" {
+		return ""
+	}
+	if s, ok := shortcutKeyNames[strings.ToLower(key)]; ok {
+		return s
+	}
+	if utf8.RuneCountInString(key) == 1 {
+		return strings.ToLower(key)
+	}
+	// function keys
+	if (key[0] == 'f' || key[0] == 'F') && len(key) <= 3 && strings.Trim(key[1:], "0123456789") == "" {
+		return "F" + key[1:]
+	}
+	return key
+}
+
+func (s Shortcut) IsValid() bool {
+	return s.Key != ""
+}
+
+// tk event sequence like <Control-Shift-Key-S> or <Command-Key-o>
+func (s Shortcut) Sequence() string {
+	if !s.IsValid() {
+		return ""
+	}
+	aqua := isAquaShortcut()
+	var ar []string
+	if s.Modifier&KeyModifierControl != 0 {
+		ar = append(ar, "Control")
+	}
+	if s.Modifier&KeyModifierAlt != 0 {
+		if aqua {
+			ar = append(ar, "Option")
+		} else {
+			ar = append(ar, "Alt")
+		}
+	}
+	if s.Modifier&KeyModifierShift != 0 {
+		ar = append(ar, "Shift")
+	}
+	if s.Modifier&KeyModifierMeta != 0 {
+		if aqua {
+			ar = append(ar, "Command")
+		} else {
+			ar = append(ar, "Meta")
+		}
+	}
+	ar = append(ar, "Key", s.eventKey())
+	return "<" + strings.Join(ar, "-") + ">"
+}
+
+// keysym of key event, shift changes letters to upper case and
+// digits or punctuation like "Shift+1" to shifted keysym exclam
+func (s Shortcut) eventKey() string {
+	if s.Modifier&KeyModifierShift == 0 {
+		return s.Key
+	}
+	if key, ok := shortcutShiftedKeys[s.Key]; ok {
+		return key
+	}
+	if utf8.RuneCountInString(s.Key) == 1 {
+		return strings.ToUpper(s.Key)
+	}
+	return s.Key
+}
+
+func (s Shortcut) keyText() string {
+	if t, ok := shortcutKeyText[s.Key]; ok {
+		return t
+	}
+	if utf8.RuneCountInString(s.Key) == 1 {
+		return strings.ToUpper(s.Key)
+	}
+	return s.Key
+}
+
+// display text for menu accelerator, "Ctrl+Shift+S" or "Command-Shift-S" on macOS
+// (tk aqua menus show modifier symbols for this form)
+func (s Shortcut) Text() string {
+	if !s.IsValid() {
+		return ""
+	}
+	if isAquaShortcut() {
+		var ar []string
+		if s.Modifier&KeyModifierControl != 0 {
+			ar = append(ar, "Control")
+		}
+		if s.Modifier&KeyModifierAlt != 0 {
+			ar = append(ar, "Option")
+		}
+		if s.Modifier&KeyModifierShift != 0 {
+			ar = append(ar, "Shift")
+		}
+		if s.Modifier&KeyModifierMeta != 0 {
+			ar = append(ar, "Command")
+		}
+		return strings.Join(append(ar, s.keyText()), "-")
+	}
+	return s.String()
+}
+
+// portable text like "Ctrl+Shift+S"
+func (s Shortcut) String() string {
+	if !s.IsValid() {
+		return ""
+	}
+	var ar []string
+	if s.Modifier&KeyModifierControl != 0 {
+		ar = append(ar, "Ctrl")
+	}
+	if s.Modifier&KeyModifierAlt != 0 {
+		ar = append(ar, "Alt")
+	}
+	if s.Modifier&KeyModifierShift != 0 {
+		ar = append(ar, "Shift")
+	}
+	if s.Modifier&KeyModifierMeta != 0 {
+		if isAquaShortcut() {
+			ar = append(ar, "Cmd")
+		} else {
+			ar = append(ar, "Meta")
+		}
+	}
+	return strings.Join(append(ar, s.keyText()), "+")
+}
+
+// key event matches shortcut
+func (s Shortcut) Match(e *KeyEvent) bool {
+	if e == nil || e.Event == nil || !s.IsValid() {
+		return false
+	}
+	mod := e.KeyModifier &^ (KeyModifierNone | KeyModifierFn)
+	return mod == s.Modifier && strings.EqualFold(e.KeySym, s.eventKey())
+}
+
+type shortcutItem struct {
+	shortcut Shortcut
+	fn       func()
+	binding  *Binding
+}
+
+// application or window shortcuts bound on one bind tag, conflicts are only
+// detected in one map. the same shortcut in window map and application map
+// are both triggered, window binding first.
+type ShortcutMap struct {
+	tag     string
+	items   map[string]*shortcutItem
+	actions map[*Action]*shortcutActionView
+}
+
+// new shortcut map on bind tag, tag is toplevel window id or "all" for application
+func NewShortcutMap(tag string) *ShortcutMap {
+	if tag == "" {
+		return nil
+	}
+	return &ShortcutMap{tag, make(map[string]*shortcutItem), make(map[*Action]*shortcutActionView)}
+}
+
+// new shortcut map for application (all bind tag)
+func NewAppShortcutMap() *ShortcutMap {
+	return NewShortcutMap("all")
+}
+
+// new shortcut map for toplevel window and it's children
+func NewWindowShortcutMap(w *Window) *ShortcutMap {
+	if !IsValidWidget(w) {
+		return nil
+	}
+	return NewShortcutMap(w.Id())
+}
+
+func (m *ShortcutMap) Tag() string {
+	return m.tag
+}
+
+// add shortcut, returns ErrExist if shortcut is already used in map,
+// other maps are not checked
+func (m *ShortcutMap) Add(shortcut Shortcut, fn func()) error {
+	if !shortcut.IsValid() || fn == nil {
+		return ErrInvalid
+	}
+	seq := shortcut.Sequence()
+	if _, ok := m.items[seq]; ok {
+		return ErrExist
+	}
+	b, err := BindEvent(m.tag, seq, func(e *Event) {
+		fn()
+	})
+	if err != nil {
+		return err
+	}
+	m.items[seq] = &shortcutItem{shortcut, fn, b}
+	return nil
+}
+
+// parse text and add shortcut
+func (m *ShortcutMap) AddText(text string, fn func()) error {
+	sc, err := ParseShortcut(text)
+	if err != nil {
+		return err
+	}
+	return m.Add(sc, fn)
+}
+
+// shortcut is used in map, other maps are not checked
+func (m *ShortcutMap) IsConflict(shortcut Shortcut) bool {
+	_, ok := m.items[shortcut.Sequence()]
+	return ok
+}
+
+func (m *ShortcutMap) Remove(shortcut Shortcut) error {
+	seq := shortcut.Sequence()
+	item, ok := m.items[seq]
+	if !ok {
+		return ErrNotExist
+	}
+	delete(m.items, seq)
+	return item.binding.Unbind()
+}
+
+// invoke shortcut function
+func (m *ShortcutMap) Invoke(shortcut Shortcut) bool {
+	item, ok := m.items[shortcut.Sequence()]
+	if !ok {
+		return false
+	}
+	item.fn()
+	return true
+}
+
+// shortcuts sorted by text
+func (m *ShortcutMap) Shortcuts() []Shortcut {
+	var list []Shortcut
+	for _, item := range m.items {
+		list = append(list, item.shortcut)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].String() < list[j].String()
+	})
+	return list
+}
+
+// remove all shortcuts and actions
+func (m *ShortcutMap) Clear() {
+	for act, view := range m.actions {
+		act.removeView(view)
+		delete(m.actions, act)
+	}
+	for seq, item := range m.items {
+		item.binding.Unbind()
+		delete(m.items, seq)
+	}
+}
+
+// shortcut of action in map
+type shortcutActionView struct {
+	m        *ShortcutMap
+	act      *Action
+	shortcut Shortcut
+}
+
+func (v *shortcutActionView) isValid() bool {
+	return v.m.actions[v.act] == v
+}
+
+func (v *shortcutActionView) bind(act *Action) error {
+	v.shortcut = Shortcut{}
+	if !act.shortcut.IsValid() {
+		return nil
+	}
+	err := v.m.Add(act.shortcut, act.Trigger)
+	if err != nil {
+		return err
+	}
+	v.shortcut = act.shortcut
+	return nil
+}
+
+func (v *shortcutActionView) updateAction(act *Action) {
+	if act.shortcut == v.shortcut {
+		return
+	}
+	if v.shortcut.IsValid() {
+		v.m.Remove(v.shortcut)
+	}
+	v.bind(act)
+}
+
+// shortcut is used in map by other action or function
+func (v *shortcutActionView) isConflict(shortcut Shortcut) bool {
+	return shortcut.Sequence() != v.shortcut.Sequence() && v.m.IsConflict(shortcut)
+}
+
+// bind action shortcut to trigger action, the binding follows action shortcut changes
+func (m *ShortcutMap) AddAction(act *Action) error {
+	if act == nil || act.IsSeparator() {
+		return ErrInvalid
+	}
+	if m.actions[act] != nil {
+		return ErrExist
+	}
+	view := &shortcutActionView{m: m, act: act}
+	err := view.bind(act)
+	if err != nil {
+		return err
+	}
+	m.actions[act] = view
+	act.addView(view)
+	return nil
+}
+
+func (m *ShortcutMap) RemoveAction(act *Action) error {
+	if act == nil {
+		return ErrInvalid
+	}
+	view := m.actions[act]
+	if view == nil {
+		return ErrNotExist
+	}
+	delete(m.actions, act)
+	act.removeView(view)
+	if view.shortcut.IsValid() {
+		return m.Remove(view.shortcut)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"testing"
+)
+
+func init() {
+	registerTest("Shortcut", testShortcut)
+}
+
+func testShortcut(t *testing.T) {
+	for _, text := range []string{"", "Ctrl+", "Hyper+S"} {
+		if _, err := ParseShortcut(text); err == nil {
+			t.Fatal("ParseShortcut", text)
+		}
+	}
+	sc := MustParseShortcut("ctrl+shift+s")
+	if sc.Modifier != KeyModifierControl|KeyModifierShift || sc.Key != "s" {
+		t.Fatal("ParseShortcut", sc)
+	}
+	if v := MustParseShortcut("Alt+F4").Key; v != "F4" {
+		t.Fatal("ParseShortcut", "F4", v)
+	}
+	if v := MustParseShortcut("Ctrl++").Key; v != "plus" {
+		t.Fatal("ParseShortcut", "plus", v)
+	}
+	for text, key := range map[string]string{"Shift+1": "exclam", "Ctrl+Shift+/": "question", "Shift+F1": "F1", "Shift+a": "A"} {
+		if v := MustParseShortcut(text).eventKey(); v != key {
+			t.Fatal("eventKey", text, key, v)
+		}
+	}
+	ke := &KeyEvent{Event: &Event{KeySym: "exclam"}, KeyModifier: KeyModifierShift}
+	if !MustParseShortcut("Shift+1").Match(ke) {
+		t.Fatal("Match", "Shift+1")
+	}
+	if !isAquaShortcut() {
+		if v := sc.Sequence(); v != "<Control-Shift-Key-S>" {
+			t.Fatal("Sequence", "<Control-Shift-Key-S>", v)
+		}
+		if v := sc.Text(); v != "Ctrl+Shift+S" {
+			t.Fatal("Text", "Ctrl+Shift+S", v)
+		}
+		if v := MustParseShortcut("Cmd+O").Sequence(); v != "<Control-Key-o>" {
+			t.Fatal("Sequence", "<Control-Key-o>", v)
+		}
+		if v := MustParseShortcut("Ctrl+PageDown").Text(); v != "Ctrl+PgDown" {
+			t.Fatal("Text", "Ctrl+PgDown", v)
+		}
+		if v := MustParseShortcut("Shift+1"); v.Sequence() != "<Shift-Key-exclam>" || v.Text() != "Shift+1" {
+			t.Fatal("Sequence", "<Shift-Key-exclam>", v.Sequence(), v.Text())
+		}
+	} else {
+		if v := MustParseShortcut("Cmd+O").Sequence(); v != "<Command-Key-o>" {
+			t.Fatal("Sequence", "<Command-Key-o>", v)
+		}
+		if v := sc.Text(); v != "Control-Shift-S" {
+			t.Fatal("Text", "Control-Shift-S", v)
+		}
+	}
+
+	w := NewWindow()
+	defer w.Destroy()
+	m := NewWindowShortcutMap(w)
+	n := 0
+	if err := m.Add(sc, func() { n++ }); err != nil {
+		t.Fatal("Add", err)
+	}
+	if err := m.AddText("Ctrl+Shift+S", func() {}); err != ErrExist {
+		t.Fatal("Add conflict", err)
+	}
+	if !m.IsConflict(MustParseShortcut("Shift+Ctrl+S")) {
+		t.Fatal("IsConflict")
+	}
+	m.AddText("F5", func() {})
+	if v := len(m.Shortcuts()); v != 2 {
+		t.Fatal("Shortcuts", 2, v)
+	}
+	if !m.Invoke(sc) || n != 1 {
+		t.Fatal("Invoke", n)
+	}
+	if v := len(w.BindInfo()); v != 2 {
+		t.Fatal("BindInfo", 2, v)
+	}
+	m.Remove(sc)
+	if m.IsConflict(sc) || len(w.BindInfo()) != 1 {
+		t.Fatal("Remove", w.BindInfo())
+	}
+	m.Clear()
+	if v := len(w.BindInfo()); v != 0 {
+		t.Fatal("Clear", 0, v)
+	}
+}
@@ -1187,5 +1187,11 @@ func (w *Tablelist) OnDoubleClickedItem(fn func(full_key string)) error {
 // ---
 
 func (w *Tablelist) BindEvent(event string, fn func(event *Event)) error {
+	_, err := w.OnEvent(event, fn)
+	return err
+}
+
+// add bind event on body tag, returns binding handle for unbind
+func (w *Tablelist) OnEvent(event string, fn func(event *Event)) (*Binding, error) {
 	return BindEvent(fmt.Sprintf("[%v bodytag]", w.id), event, fn)
 }
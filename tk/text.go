@@ -28,6 +28,41 @@ func NewText(parent Widget, attributes ...*WidgetAttr) *Text {
 	return w
 }
 
+// create text peer sharing the document of text, use TextAttrStartLine and
+// TextAttrEndLine to show part of the document
+func NewTextPeer(parent Widget, text *Text, attributes ...*WidgetAttr) *Text {
+	if !IsValidWidget(text) {
+		return nil
+	}
+	iid := makeNamedWidgetId(parent, "atk_text")
+	script := fmt.Sprintf("%v peer create %v", text.id, iid)
+	if len(attributes) > 0 {
+		extra := buildWidgetAttributeScript(text.info.MetaClass, text.info.IsTtk, attributes)
+		if len(extra) > 0 {
+			script += " " + extra
+		}
+	}
+	if eval(script) != nil {
+		return nil
+	}
+	w := &Text{}
+	if w.Attach(iid) != nil {
+		return nil
+	}
+	return w
+}
+
+// peers of text, not including text
+func (w *Text) Peers() (list []*Text) {
+	ids, _ := evalAsStringList(fmt.Sprintf("%v peer names", w.id))
+	for _, id := range ids {
+		if peer, ok := FindWidget(id).(*Text); ok {
+			list = append(list, peer)
+		}
+	}
+	return
+}
+
 func (w *Text) Attach(id string) error {
 	info, err := CheckWidgetInfo(id, WidgetTypeText)
 	if err != nil {
@@ -364,6 +399,74 @@ func (w *Text) IsEnableUndo() bool {
 	return r
 }
 
+// undo last edit action, error if undo stack is empty
+func (w *Text) Undo() error {
+	return eval(fmt.Sprintf("%v edit undo", w.id))
+}
+
+// redo last undone edit action, error if redo stack is empty
+func (w *Text) Redo() error {
+	return eval(fmt.Sprintf("%v edit redo", w.id))
+}
+
+// insert separator to undo stack, edits between separators undo as one action
+func (w *Text) AddUndoSeparator() error {
+	return eval(fmt.Sprintf("%v edit separator", w.id))
+}
+
+// clear undo and redo stacks
+func (w *Text) ResetUndo() error {
+	return eval(fmt.Sprintf("%v edit reset", w.id))
+}
+
+func (w *Text) CanUndo() bool {
+	if !mainInterp.SupportTk86() {
+		return false
+	}
+	r, _ := evalAsBool(fmt.Sprintf("%v edit canundo", w.id))
+	return r
+}
+
+func (w *Text) CanRedo() bool {
+	if !mainInterp.SupportTk86() {
+		return false
+	}
+	r, _ := evalAsBool(fmt.Sprintf("%v edit canredo", w.id))
+	return r
+}
+
+func (w *Text) SetModified(modified bool) error {
+	return eval(fmt.Sprintf("%v edit modified {%v}", w.id, boolToInt(modified)))
+}
+
+func (w *Text) IsModified() bool {
+	r, _ := evalAsBool(fmt.Sprintf("%v edit modified", w.id))
+	return r
+}
+
+// bind <<Modified>> event, fires when the modified flag changes
+func (w *Text) OnModified(fn func()) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	return w.BindEvent("<<Modified>>", func(e *Event) {
+		fn()
+	})
+}
+
+// bind <<UndoStack>> event, fires when undo stack becomes empty or not empty (Tk 8.6)
+func (w *Text) OnUndoStackChanged(fn func()) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	if !mainInterp.SupportTk86() {
+		return ErrUnsupport
+	}
+	return w.BindEvent("<<UndoStack>>", func(e *Event) {
+		fn()
+	})
+}
+
 func (w *Text) SetReadOnly(b bool) error {
 	var script string
 	if b {
@@ -379,14 +482,6 @@ func (w *Text) IsReadOnly() bool {
 	return r != "normal"
 }
 
-type TextIndex struct {
-	index string
-}
-
-func (t TextIndex) String() string {
-	return t.index
-}
-
 func (w *Text) TextLength() int {
 	r, _ := evalAsInt(fmt.Sprintf("%v count -chars 1.0 end", w.id))
 	return r
@@ -402,9 +497,9 @@ func (w *Text) LineCount() int {
 	return r
 }
 
-func (w *Text) InsertText(pos int, text string) error {
+func (w *Text) InsertText(index TextIndex, text string) error {
 	setObjText("atk_text_insert", text)
-	return eval(fmt.Sprintf("%v insert {0.0 + %v chars} $atk_text_insert", w.id, pos))
+	return eval(fmt.Sprintf("%v insert {%v} $atk_text_insert", w.id, index))
 }
 
 func (w *Text) AppendText(text string) error {
@@ -496,6 +591,22 @@ func NewTextEx(parent Widget, attributs ...*WidgetAttr) *TextEx {
 	return w
 }
 
+// create text peer with scroll bars sharing the document of text
+func NewTextExPeer(parent Widget, text *Text, attributs ...*WidgetAttr) *TextEx {
+	peer := NewTextPeer(parent, text, attributs...)
+	if peer == nil {
+		return nil
+	}
+	w := &TextEx{}
+	w.ScrollLayout = NewScrollLayout(parent)
+	w.Text = peer
+	w.SetWidget(w.Text)
+	w.Text.BindXScrollBar(w.XScrollBar)
+	w.Text.BindYScrollBar(w.YScrollBar)
+	RegisterWidget(w)
+	return w
+}
+
 func TextAttrBackground(color string) *WidgetAttr {
 	return &WidgetAttr{"background", color}
 }
@@ -6,6 +6,14 @@ import "testing"
 
 func init() {
 	registerTest("Text", testText)
+	registerTest("TextTag", testTextTag)
+	registerTest("TextIndex", testTextIndex)
+	registerTest("TextMark", testTextMark)
+	registerTest("TextSearch", testTextSearch)
+	registerTest("TextUndo", testTextUndo)
+	registerTest("TextEmbed", testTextEmbed)
+	registerTest("TextDump", testTextDump)
+	registerTest("TextPeer", testTextPeer)
 }
 
 func testText(t *testing.T) {
@@ -152,3 +160,378 @@ func testText(t *testing.T) {
 		t.Fatal("IsEnableUndo", true, v)
 	}
 }
+
+func testTextTag(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.SetText("hello world\nsecond line")
+	red := w.CreateTag("red", TextTagAttrForeground("red"), TextTagAttrUnderline(true))
+	if v := red.NativeAttribute("foreground"); v != "red" {
+		t.Fatal("CreateTag", v)
+	}
+	red.AddRange("1.0", "1.5")
+	red.AddRange("2.0", "2.6")
+	if v := red.Ranges(); len(v) != 2 || v[0].Start != "1.0" || v[0].End != "1.5" || v[1].Start != "2.0" {
+		t.Fatal("Ranges", v)
+	}
+	if r, ok := red.NextRange("1.3", ""); !ok || r.Start != "1.3" || r.End != "1.5" {
+		t.Fatal("NextRange", r, ok)
+	}
+	if r, ok := red.PrevRange("end", ""); !ok || r.Start != "2.0" {
+		t.Fatal("PrevRange", r, ok)
+	}
+	red.RemoveRange("2.0", "end")
+	if v := w.TagRanges("red"); len(v) != 1 {
+		t.Fatal("RemoveRange", v)
+	}
+
+	w.AddTag("bold", "1.0", "1.2")
+	if v := w.TagNames("1.0"); len(v) != 2 || v[0] != "red" || v[1] != "bold" {
+		t.Fatal("TagNames", v)
+	}
+	red.Raise(nil)
+	if v := w.TagNames("1.0"); v[1] != "red" {
+		t.Fatal("Raise", v)
+	}
+	red.Lower(w.Tag("bold"))
+	if v := w.TagNames("1.0"); v[0] != "red" {
+		t.Fatal("Lower", v)
+	}
+
+	w.AppendTaggedText("\nlink", "link")
+	link := w.Tag("link")
+	if !link.IsValid() || len(link.Ranges()) != 1 {
+		t.Fatal("AppendTaggedText", link.Ranges())
+	}
+	lb, err := link.BindEvent("<ButtonPress-1>", func(e *Event) {})
+	if err != nil {
+		t.Fatal("BindEvent", err)
+	}
+	if v := link.BindInfo(); len(v) != 1 {
+		t.Fatal("BindInfo", v)
+	}
+	if err := lb.Unbind(); err != nil || len(link.BindInfo()) != 0 {
+		t.Fatal("Unbind", err, link.BindInfo())
+	}
+	link.Delete()
+	if link.IsValid() {
+		t.Fatal("Delete")
+	}
+}
+
+func testTextIndex(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.SetText("hello world\n中文 line")
+	if v := NewTextIndex(2, 3); v != "2.3" {
+		t.Fatal("NewTextIndex", "2.3", v)
+	}
+	if l, c, ok := TextIndex("2.3").LineChar(); !ok || l != 2 || c != 3 {
+		t.Fatal("LineChar", l, c, ok)
+	}
+	if v := w.Index(TextIndexStart.Lines(1).LineEnd()); v != "2.7" {
+		t.Fatal("LineEnd", "2.7", v)
+	}
+	if v := w.Index(NewTextIndex(1, 2).WordEnd()); v != "1.5" {
+		t.Fatal("WordEnd", "1.5", v)
+	}
+	if v := w.Index(TextIndexEnd.Chars(-2)); v != "2.6" {
+		t.Fatal("Chars", "2.6", v)
+	}
+	if v := w.IndexToOffset("2.1"); v != 13 {
+		t.Fatal("IndexToOffset", 13, v)
+	}
+	if v := w.OffsetToIndex(13); v != "2.1" {
+		t.Fatal("OffsetToIndex", "2.1", v)
+	}
+	if v := w.Get("2.0", "2.2"); v != "中文" {
+		t.Fatal("Get", "中文", v)
+	}
+	if v := w.CompareIndex("1.5", "2.0"); v != -1 {
+		t.Fatal("CompareIndex", -1, v)
+	}
+	if v := w.CompareIndex("2.0", "1.0 +12 chars"); v != 0 {
+		t.Fatal("CompareIndex", 0, v)
+	}
+	w.InsertText(NewTextIndex(1, 5), ",")
+	w.Replace("1.0", "1.5", "HELLO")
+	w.Delete("1.5", "")
+	if v := w.Get("1.0", TextIndexStart.LineEnd()); v != "HELLO world" {
+		t.Fatal("InsertText", "HELLO world", v)
+	}
+}
+
+func testTextMark(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.SetText("hello world")
+	a := w.SetMark("a", "1.5")
+	if a == nil || a.Index() != "1.5" {
+		t.Fatal("SetMark", a)
+	}
+	if v := a.Gravity(); v != TextMarkGravityRight {
+		t.Fatal("Gravity", TextMarkGravityRight, v)
+	}
+	w.InsertText(a.TextIndex(), "!")
+	if v := a.Index(); v != "1.6" {
+		t.Fatal("GravityRight", "1.6", v)
+	}
+	a.SetGravity(TextMarkGravityLeft)
+	w.InsertText(a.TextIndex(), "?")
+	if v := a.Index(); v != "1.6" {
+		t.Fatal("GravityLeft", "1.6", v)
+	}
+	w.SetMark("b", "1.8")
+	if m := a.Next(); m == nil || m.Name() != "b" {
+		t.Fatal("Next", m)
+	}
+	if m := w.PreviousMark("1.7"); m == nil || m.Name() != "a" {
+		t.Fatal("PreviousMark", m)
+	}
+	w.SetInsertCursor("1.2")
+	w.InsertText("1.0", "xx")
+	if v := w.InsertCursor(); v != "1.4" {
+		t.Fatal("InsertCursor", "1.4", v)
+	}
+	a.Delete()
+	if a.IsValid() || w.Mark("a") != nil {
+		t.Fatal("Delete")
+	}
+}
+
+func testTextSearch(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.SetText("中文 hello\nHello world\nhello")
+	if r, ok := w.SearchFirst("hello", TextIndexStart); !ok || r.Start != "1.3" || r.End != "1.8" {
+		t.Fatal("SearchFirst", r, ok)
+	}
+	if v, err := w.Search("hello", TextIndexStart, TextSearchAttrAll(), TextSearchAttrNoCase()); err != nil || len(v) != 3 {
+		t.Fatal("Search", v, err)
+	}
+	if r, ok := w.SearchFirst("hello", TextIndexEnd, TextSearchAttrBackwards()); !ok || r.Start != "3.0" {
+		t.Fatal("SearchBackwards", r, ok)
+	}
+	if _, ok := w.SearchFirst("hello", "2.0", TextSearchAttrStopIndex("3.0")); ok {
+		t.Fatal("SearchStopIndex")
+	}
+	if v, err := w.Search(`w\w+d`, TextIndexStart, TextSearchAttrRegexp()); err != nil || len(v) != 1 || v[0].Start != "2.6" || v[0].End != "2.11" {
+		t.Fatal("SearchRegexp", v, err)
+	}
+	if v, err := w.Search(`(?P<w>wor)ld`, TextIndexStart, TextSearchAttrRegexp()); err != nil || len(v) != 1 || v[0].Start != "2.6" {
+		t.Fatal("SearchGoRegexp", v, err)
+	}
+	if _, err := w.Search(`w\w+d`, "bad", TextSearchAttrRegexp()); err == nil {
+		t.Fatal("Search bad index")
+	}
+	if _, err := w.Search(`(?P<w>wor)ld`, "bad", TextSearchAttrRegexp()); err == nil {
+		t.Fatal("SearchGoRegexp bad index")
+	}
+	// embedded image takes one index before the match
+	w.InsertImage("2.0", NewImage())
+	if v, err := w.Search(`(?P<w>wor)ld`, TextIndexStart, TextSearchAttrRegexp()); err != nil || len(v) != 1 || v[0].Start != "2.7" || v[0].End != "2.12" {
+		t.Fatal("SearchGoRegexp embed", v, err)
+	}
+	w.Delete("2.0", "")
+	if n, err := w.ReplaceAll(`h(ello)`, `j\1`, TextSearchAttrRegexp(), TextSearchAttrNoCase()); err != nil || n != 3 {
+		t.Fatal("ReplaceAll", n, err)
+	}
+	if v := w.Get(TextIndexStart, TextIndexEnd.Chars(-1)); v != "中文 jello\njello world\njello" {
+		t.Fatal("ReplaceAll", v)
+	}
+	// anchors match the line, not the match text
+	if n, err := w.ReplaceAll(`^j(\w+)`, `<&>`, TextSearchAttrRegexp()); err != nil || n != 2 {
+		t.Fatal("ReplaceAll anchor", n, err)
+	}
+	if v := w.Get(TextIndexStart, TextIndexEnd.Chars(-1)); v != "中文 jello\n<jello> world\n<jello>" {
+		t.Fatal("ReplaceAll anchor", v)
+	}
+	if _, err := w.ReplaceAll(`(?P<w>wor)ld`, "x", TextSearchAttrRegexp()); err == nil {
+		t.Fatal("ReplaceAll invalid pattern")
+	}
+}
+
+func testTextUndo(t *testing.T) {
+	w := NewText(nil, TextAttrEnableUndo(true))
+	defer w.Destroy()
+
+	w.InsertText(TextIndexEnd, "hello")
+	w.AddUndoSeparator()
+	w.InsertText(TextIndexEnd, " world")
+	if !w.IsModified() {
+		t.Fatal("IsModified", true)
+	}
+	w.SetModified(false)
+	if w.IsModified() {
+		t.Fatal("SetModified", false)
+	}
+	if mainInterp.SupportTk86() && !w.CanUndo() {
+		t.Fatal("CanUndo", true)
+	}
+	if err := w.Undo(); err != nil {
+		t.Fatal("Undo", err)
+	}
+	if v := w.Get(TextIndexStart, "1.0 lineend"); v != "hello" {
+		t.Fatal("Undo", "hello", v)
+	}
+	if mainInterp.SupportTk86() && !w.CanRedo() {
+		t.Fatal("CanRedo", true)
+	}
+	w.Redo()
+	if v := w.Get(TextIndexStart, "1.0 lineend"); v != "hello world" {
+		t.Fatal("Redo", "hello world", v)
+	}
+	w.ResetUndo()
+	if err := w.Undo(); err == nil {
+		t.Fatal("ResetUndo")
+	}
+	if err := w.OnModified(func() {}); err != nil {
+		t.Fatal("OnModified", err)
+	}
+}
+
+func testTextEmbed(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.SetText("hello world")
+	btn := NewButton(w, "OK")
+	if err := w.InsertWindow("1.5", btn, TextEmbedAttrAlign(TextEmbedAlignBaseline), TextEmbedAttrPadX(2)); err != nil {
+		t.Fatal("InsertWindow", err)
+	}
+	if v := w.WindowIndex(btn); v != "1.5" {
+		t.Fatal("WindowIndex", "1.5", v)
+	}
+	if v := w.WindowAlign(btn); v != TextEmbedAlignBaseline {
+		t.Fatal("WindowAlign", TextEmbedAlignBaseline, v)
+	}
+	if v := w.Windows(); len(v) != 1 || v[0] != Widget(btn) {
+		t.Fatal("Windows", v)
+	}
+	// offsets count embedded window as one char
+	for _, index := range []TextIndex{"1.3", "1.5", "1.6", "1.9"} {
+		if v := w.OffsetToIndex(w.IndexToOffset(index)); v != index {
+			t.Fatal("OffsetToIndex(IndexToOffset)", index, v)
+		}
+	}
+	if v := w.IndexToOffset("1.9"); v != 9 {
+		t.Fatal("IndexToOffset", 9, v)
+	}
+
+	img := NewImage()
+	name, err := w.InsertImage(TextIndexEnd, img, TextEmbedAttrName("icon"), TextEmbedAttrAlign(TextEmbedAlignTop))
+	if err != nil || name != "icon" {
+		t.Fatal("InsertImage", name, err)
+	}
+	if v := w.ImageIndex(name); v != "1.12" {
+		t.Fatal("ImageIndex", "1.12", v)
+	}
+	if v := w.Image(name); v == nil || v.Id() != img.Id() {
+		t.Fatal("Image", v)
+	}
+	w.ConfigureImage(name, TextEmbedAttrAlign(TextEmbedAlignBottom))
+	if v := w.ImageAlign(name); v != TextEmbedAlignBottom {
+		t.Fatal("ImageAlign", TextEmbedAlignBottom, v)
+	}
+	if v := w.ImageNames(); len(v) != 1 || v[0] != "icon" {
+		t.Fatal("ImageNames", v)
+	}
+}
+
+func testTextDump(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.CreateTag("bold", TextTagAttrUnderline(true))
+	w.CreateTag("h1", TextTagAttrForeground("blue"))
+	w.InsertTaggedText(TextIndexEnd, "Title", "h1")
+	w.AppendText("\nhello ")
+	w.InsertTaggedText(TextIndexEnd, "world", "bold")
+	w.SetMark("m", "2.2")
+	v := w.Dump("2.0", "2.end")
+	if len(v) < 4 || v[0].Type != TextSegmentText || v[0].Value != "he" ||
+		v[1].Type != TextSegmentMark || v[1].Value != "m" || v[1].Index != "2.2" {
+		t.Fatal("Dump", v)
+	}
+
+	doc := w.Document()
+	data, err := doc.ToJSON()
+	if err != nil {
+		t.Fatal("ToJSON", err)
+	}
+	doc, err = ParseTextDocumentJSON(data)
+	if err != nil {
+		t.Fatal("ParseTextDocumentJSON", err)
+	}
+	w2 := NewText(nil)
+	defer w2.Destroy()
+	if err := w2.Load(doc); err != nil {
+		t.Fatal("Load", err)
+	}
+	if v := w2.Get(TextIndexStart, TextIndexEnd.Chars(-1)); v != "Title\nhello world" {
+		t.Fatal("Load", v)
+	}
+	if v := w2.TagRanges("bold"); len(v) != 1 || v[0].Start != "2.6" || v[0].End != "2.11" {
+		t.Fatal("Load tag", v)
+	}
+	if v := w2.Tag("h1").NativeAttribute("foreground"); v != "blue" {
+		t.Fatal("Load tag option", v)
+	}
+	if m := w2.Mark("m"); m == nil || m.Index() != "2.2" || m.Gravity() != TextMarkGravityRight {
+		t.Fatal("Load mark", m)
+	}
+	w.Mark("m").SetGravity(TextMarkGravityLeft)
+	w2.Load(w.Document())
+	if m := w2.Mark("m"); m == nil || m.Gravity() != TextMarkGravityLeft {
+		t.Fatal("Load mark gravity", m)
+	}
+
+	md := doc.ToMarkdown()
+	if md != "# Title\nhello **world**" {
+		t.Fatal("ToMarkdown", md)
+	}
+	// heading tag on each line and not at line start
+	w.AddTag("h1", "2.3", TextIndexEnd)
+	if md := w.Document().ToMarkdown(); md != "# Title\n# hello **world**" {
+		t.Fatal("ToMarkdown heading", md)
+	}
+	w2.Load(ParseTextDocumentMarkdown("a *b* `c*` \\*d\n## e"))
+	if v := w2.Get(TextIndexStart, TextIndexEnd.Chars(-1)); v != "a b c* *d\ne" {
+		t.Fatal("ParseTextDocumentMarkdown", v)
+	}
+	if v := w2.TagRanges("code"); len(v) != 1 || v[0].Start != "1.4" || v[0].End != "1.6" {
+		t.Fatal("ParseTextDocumentMarkdown code", v)
+	}
+	if v := w2.TagNames("2.0"); len(v) != 1 || v[0] != "h2" {
+		t.Fatal("ParseTextDocumentMarkdown heading", v)
+	}
+}
+
+func testTextPeer(t *testing.T) {
+	w := NewText(nil)
+	defer w.Destroy()
+
+	w.SetText("line1\nline2\nline3")
+	peer := NewTextPeer(nil, w, TextAttrStartLine(2), TextAttrEndLine(3))
+	if peer == nil {
+		t.Fatal("NewTextPeer")
+	}
+	defer peer.Destroy()
+	if v := peer.StartLine(); v != 2 {
+		t.Fatal("StartLine", 2, v)
+	}
+	if v := peer.Get(TextIndexStart, TextIndexEnd.Chars(-1)); v != "line2" {
+		t.Fatal("NewTextPeer", "line2", v)
+	}
+	peer.InsertText(TextIndexStart, "peer ")
+	if v := w.Get("2.0", "2.end"); v != "peer line2" {
+		t.Fatal("NewTextPeer share", "peer line2", v)
+	}
+	if v := w.Peers(); len(v) != 1 || v[0] != peer {
+		t.Fatal("Peers", v)
+	}
+}
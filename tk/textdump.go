@@ -0,0 +1,439 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type TextSegmentType int
+
+const (
+	TextSegmentText TextSegmentType = iota
+	TextSegmentTagOn
+	TextSegmentTagOff
+	TextSegmentMark
+	TextSegmentImage
+	TextSegmentWindow
+)
+
+var (
+	textSegmentTypeName = []string{"text", "tagon", "tagoff", "mark", "image", "window"}
+)
+
+func (v TextSegmentType) String() string {
+	if v >= 0 && int(v) < len(textSegmentTypeName) {
+		return textSegmentTypeName[v]
+	}
+	return ""
+}
+
+func parserTextSegmentTypeResult(r string, err error) TextSegmentType {
+	if err != nil {
+		return -1
+	}
+	for n, s := range textSegmentTypeName {
+		if s == r {
+			return TextSegmentType(n)
+		}
+	}
+	return -1
+}
+
+func (v TextSegmentType) MarshalText() ([]byte, error) {
+	s := v.String()
+	if s == "" {
+		return nil, ErrInvalid
+	}
+	return []byte(s), nil
+}
+
+func (v *TextSegmentType) UnmarshalText(data []byte) error {
+	t := parserTextSegmentTypeResult(string(data), nil)
+	if t == -1 {
+		return ErrInvalid
+	}
+	*v = t
+	return nil
+}
+
+// text dump segment
+// Value is text, tag name, mark name, image name or widget id
+// Data is photo image id for image segment and gravity for mark segment
+type TextSegment struct {
+	Type  TextSegmentType `json:"type"`
+	Value string          `json:"value"`
+	Data  string          `json:"data,omitempty"`
+	Index TextIndex       `json:"index,omitempty"`
+}
+
+// dump text, tag toggles, marks, images and windows in range start,end (not including end)
+func (w *Text) Dump(start TextIndex, end TextIndex) []TextSegment {
+	r, err := evalAsStringList(fmt.Sprintf("%v dump -all {%v} {%v}", w.id, start, end))
+	if err != nil {
+		return nil
+	}
+	var list []TextSegment
+	for i := 0; i+2 < len(r); i += 3 {
+		typ := parserTextSegmentTypeResult(r[i], nil)
+		if typ == -1 {
+			continue
+		}
+		seg := TextSegment{Type: typ, Value: r[i+1], Index: TextIndex(r[i+2])}
+		if typ == TextSegmentImage {
+			setObjText("atk_tmp_imagename", seg.Value)
+			seg.Data, _ = evalAsString(fmt.Sprintf("%v image cget $atk_tmp_imagename -image", w.id))
+		} else if typ == TextSegmentMark {
+			setObjText("atk_tmp_markname", seg.Value)
+			seg.Data, _ = evalAsString(fmt.Sprintf("%v mark gravity $atk_tmp_markname", w.id))
+		}
+		list = append(list, seg)
+	}
+	return list
+}
+
+// text tag name and configured options
+type TextDocumentTag struct {
+	Name    string            `json:"name"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// text document, tags in priority order (lowest first) and content segments
+type TextDocument struct {
+	Tags     []TextDocumentTag `json:"tags,omitempty"`
+	Segments []TextSegment     `json:"segments"`
+}
+
+func (w *Text) tagOptions(name string) map[string]string {
+	setObjText("atk_tmp_tag", name)
+	// option value pairs of changed options from {-option dbname dbclass default value} list
+	r, err := evalAsStringList(fmt.Sprintf("set atk_tmp_opts {}; foreach item [%v tag configure $atk_tmp_tag] { if {[lindex $item 4] ne [lindex $item 3]} { lappend atk_tmp_opts [lindex $item 0] [lindex $item 4] } }; set atk_tmp_opts", w.id))
+	if err != nil {
+		return nil
+	}
+	opts := make(map[string]string)
+	for i := 0; i+1 < len(r); i += 2 {
+		opts[strings.TrimPrefix(r[i], "-")] = r[i+1]
+	}
+	return opts
+}
+
+// document of text content, tags except sel
+func (w *Text) Document() *TextDocument {
+	doc := &TextDocument{}
+	for _, name := range w.TagNames("") {
+		if name == "sel" {
+			continue
+		}
+		doc.Tags = append(doc.Tags, TextDocumentTag{name, w.tagOptions(name)})
+	}
+	for _, seg := range w.Dump(TextIndexStart, TextIndexEnd.Chars(-1)) {
+		if (seg.Type == TextSegmentTagOn || seg.Type == TextSegmentTagOff) && seg.Value == "sel" {
+			continue
+		}
+		if seg.Type == TextSegmentMark && seg.Value == "current" {
+			continue
+		}
+		doc.Segments = append(doc.Segments, seg)
+	}
+	return doc
+}
+
+// replace text content with document
+func (w *Text) Load(doc *TextDocument) error {
+	if doc == nil {
+		return ErrInvalid
+	}
+	err := w.Clear()
+	if err != nil {
+		return err
+	}
+	return w.InsertDocument(TextIndexStart, doc)
+}
+
+// insert document at index, images are found by photo id in segment data
+// and windows by widget id, missing images and windows are skipped
+func (w *Text) InsertDocument(index TextIndex, doc *TextDocument) error {
+	if doc == nil {
+		return ErrInvalid
+	}
+	for _, tag := range doc.Tags {
+		var attrs []*TextTagAttr
+		for k, v := range tag.Options {
+			attrs = append(attrs, &TextTagAttr{k, v})
+		}
+		if w.CreateTag(tag.Name, attrs...) == nil {
+			return ErrInvalid
+		}
+	}
+	pos := w.SetMark("atk_text_load", index)
+	if pos == nil {
+		return ErrInvalid
+	}
+	defer pos.Delete()
+	type markIndex struct {
+		name    string
+		index   TextIndex
+		gravity TextMarkGravity
+	}
+	var active []string
+	var marks []markIndex
+	var err error
+	for _, seg := range doc.Segments {
+		switch seg.Type {
+		case TextSegmentText:
+			err = w.InsertTaggedText(pos.TextIndex(), seg.Value, active...)
+		case TextSegmentTagOn:
+			active = append(active, seg.Value)
+		case TextSegmentTagOff:
+			for i, name := range active {
+				if name == seg.Value {
+					active = append(active[:i], active[i+1:]...)
+					break
+				}
+			}
+		case TextSegmentMark:
+			// set marks after load, insertion at position moves right gravity marks
+			marks = append(marks, markIndex{seg.Value, pos.Index(), parserTextMarkGravityResult(seg.Data, nil)})
+		case TextSegmentImage:
+			img := parserImageResult(seg.Data, nil)
+			if img == nil {
+				continue
+			}
+			_, err = w.InsertImage(pos.TextIndex(), img, TextEmbedAttrName(seg.Value))
+			if err == nil {
+				err = w.addEmbedTags(pos.TextIndex().Chars(-1), active)
+			}
+		case TextSegmentWindow:
+			widget, ok := LookupWidget(seg.Value)
+			if !ok {
+				continue
+			}
+			err = w.InsertWindow(pos.TextIndex(), widget)
+			if err == nil {
+				err = w.addEmbedTags(pos.TextIndex().Chars(-1), active)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range marks {
+		mark := w.SetMark(m.name, m.index)
+		if mark != nil && m.gravity != -1 {
+			mark.SetGravity(m.gravity)
+		}
+	}
+	return nil
+}
+
+func (w *Text) addEmbedTags(index TextIndex, tags []string) error {
+	for _, tag := range tags {
+		err := w.AddTag(tag, index, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *TextDocument) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func ParseTextDocumentJSON(data []byte) (*TextDocument, error) {
+	doc := &TextDocument{}
+	err := json.Unmarshal(data, doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// tag names used by markdown subset
+const (
+	TextDocumentTagBold     = "bold"
+	TextDocumentTagItalic   = "italic"
+	TextDocumentTagCode     = "code"
+	TextDocumentTagHeading1 = "h1"
+	TextDocumentTagHeading2 = "h2"
+	TextDocumentTagHeading3 = "h3"
+)
+
+var (
+	textMarkdownInline = map[string]string{
+		TextDocumentTagBold:   "**",
+		TextDocumentTagItalic: "*",
+		TextDocumentTagCode:   "`",
+	}
+	textMarkdownHeading = map[string]string{
+		TextDocumentTagHeading1: "# ",
+		TextDocumentTagHeading2: "## ",
+		TextDocumentTagHeading3: "### ",
+	}
+)
+
+func escapeTextMarkdown(text string, lineStart bool) string {
+	var buf strings.Builder
+	for _, r := range text {
+		switch r {
+		case '\\', '*', '`':
+			buf.WriteRune('\\')
+		case '#':
+			if lineStart {
+				buf.WriteRune('\\')
+			}
+		}
+		buf.WriteRune(r)
+		lineStart = r == '\n'
+	}
+	return buf.String()
+}
+
+// markdown line and heading tag of line
+type textMarkdownLine struct {
+	heading string
+	buf     strings.Builder
+}
+
+// markdown subset of document, supports bold, italic, code and heading (h1-h3) tags,
+// a line with text in heading tag is written as heading, other tags, marks, images and windows are dropped
+func (d *TextDocument) ToMarkdown() string {
+	lines := []*textMarkdownLine{{}}
+	var active []string
+	var heading []string
+	for _, seg := range d.Segments {
+		line := lines[len(lines)-1]
+		switch seg.Type {
+		case TextSegmentText:
+			for i, s := range strings.Split(seg.Value, "\n") {
+				if i > 0 {
+					line = &textMarkdownLine{}
+					lines = append(lines, line)
+				}
+				if s == "" {
+					continue
+				}
+				if len(heading) > 0 && line.heading == "" {
+					line.heading = heading[len(heading)-1]
+				}
+				line.buf.WriteString(escapeTextMarkdown(s, line.buf.Len() == 0))
+			}
+		case TextSegmentTagOn:
+			if _, ok := textMarkdownHeading[seg.Value]; ok {
+				heading = append(heading, seg.Value)
+			} else if s, ok := textMarkdownInline[seg.Value]; ok {
+				line.buf.WriteString(s)
+				active = append(active, seg.Value)
+			}
+		case TextSegmentTagOff:
+			for i, name := range heading {
+				if name == seg.Value {
+					heading = append(heading[:i], heading[i+1:]...)
+					break
+				}
+			}
+			for i, name := range active {
+				if name == seg.Value {
+					line.buf.WriteString(textMarkdownInline[name])
+					active = append(active[:i], active[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	// close tags end at the end of text
+	line := lines[len(lines)-1]
+	for i := len(active) - 1; i >= 0; i-- {
+		line.buf.WriteString(textMarkdownInline[active[i]])
+	}
+	var buf strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(textMarkdownHeading[line.heading])
+		buf.WriteString(line.buf.String())
+	}
+	return buf.String()
+}
+
+type textMarkdownParser struct {
+	doc    *TextDocument
+	text   strings.Builder
+	active map[string]bool
+}
+
+func (p *textMarkdownParser) flush() {
+	if p.text.Len() > 0 {
+		p.doc.Segments = append(p.doc.Segments, TextSegment{Type: TextSegmentText, Value: p.text.String()})
+		p.text.Reset()
+	}
+}
+
+func (p *textMarkdownParser) toggle(tag string) {
+	p.flush()
+	if p.active[tag] {
+		delete(p.active, tag)
+		p.doc.Segments = append(p.doc.Segments, TextSegment{Type: TextSegmentTagOff, Value: tag})
+	} else {
+		p.active[tag] = true
+		p.doc.Segments = append(p.doc.Segments, TextSegment{Type: TextSegmentTagOn, Value: tag})
+	}
+}
+
+// parse markdown subset written by ToMarkdown, document tags have no options
+func ParseTextDocumentMarkdown(src string) *TextDocument {
+	p := &textMarkdownParser{doc: &TextDocument{}, active: make(map[string]bool)}
+	for _, tag := range []string{TextDocumentTagHeading1, TextDocumentTagHeading2, TextDocumentTagHeading3,
+		TextDocumentTagBold, TextDocumentTagItalic, TextDocumentTagCode} {
+		p.doc.Tags = append(p.doc.Tags, TextDocumentTag{Name: tag})
+	}
+	lines := strings.Split(src, "\n")
+	for n, line := range lines {
+		heading := ""
+		for _, tag := range []string{TextDocumentTagHeading3, TextDocumentTagHeading2, TextDocumentTagHeading1} {
+			if strings.HasPrefix(line, textMarkdownHeading[tag]) {
+				heading = tag
+				line = line[len(textMarkdownHeading[tag]):]
+				p.toggle(tag)
+				break
+			}
+		}
+		runes := []rune(line)
+		for i := 0; i < len(runes); i++ {
+			r := runes[i]
+			switch {
+			case r == '\\' && i+1 < len(runes):
+				i++
+				p.text.WriteRune(runes[i])
+			case r == '`':
+				p.toggle(TextDocumentTagCode)
+			case p.active[TextDocumentTagCode]:
+				p.text.WriteRune(r)
+			case r == '*' && i+1 < len(runes) && runes[i+1] == '*':
+				i++
+				p.toggle(TextDocumentTagBold)
+			case r == '*':
+				p.toggle(TextDocumentTagItalic)
+			default:
+				p.text.WriteRune(r)
+			}
+		}
+		if heading != "" {
+			p.toggle(heading)
+		}
+		if n+1 < len(lines) {
+			p.text.WriteRune('\n')
+		}
+	}
+	p.flush()
+	for _, tag := range []string{TextDocumentTagBold, TextDocumentTagItalic, TextDocumentTagCode} {
+		if p.active[tag] {
+			p.toggle(tag)
+		}
+	}
+	return p.doc
+}
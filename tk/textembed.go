@@ -0,0 +1,177 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// vertical align of embedded window or image in text line
+type TextEmbedAlign int
+
+const (
+	TextEmbedAlignCenter TextEmbedAlign = iota
+	TextEmbedAlignTop
+	TextEmbedAlignBottom
+	TextEmbedAlignBaseline
+)
+
+var (
+	textEmbedAlignName = []string{"center", "top", "bottom", "baseline"}
+)
+
+func (v TextEmbedAlign) String() string {
+	if v >= 0 && int(v) < len(textEmbedAlignName) {
+		return textEmbedAlignName[v]
+	}
+	return ""
+}
+
+func parserTextEmbedAlignResult(r string, err error) TextEmbedAlign {
+	if err != nil {
+		return -1
+	}
+	for n, s := range textEmbedAlignName {
+		if s == r {
+			return TextEmbedAlign(n)
+		}
+	}
+	return -1
+}
+
+// text embedded window or image attribute
+type TextEmbedAttr struct {
+	Key   string
+	Value interface{}
+}
+
+func TextEmbedAttrAlign(align TextEmbedAlign) *TextEmbedAttr {
+	return &TextEmbedAttr{"align", align}
+}
+
+func TextEmbedAttrPadX(padx int) *TextEmbedAttr {
+	return &TextEmbedAttr{"padx", padx}
+}
+
+func TextEmbedAttrPadY(pady int) *TextEmbedAttr {
+	return &TextEmbedAttr{"pady", pady}
+}
+
+// window only, stretch window to line height
+func TextEmbedAttrStretch(stretch bool) *TextEmbedAttr {
+	return &TextEmbedAttr{"stretch", boolToInt(stretch)}
+}
+
+// image only, image name in text, default is image id
+func TextEmbedAttrName(name string) *TextEmbedAttr {
+	return &TextEmbedAttr{"name", name}
+}
+
+func buildTextEmbedAttributeScript(attributes []*TextEmbedAttr) string {
+	var list []string
+	for _, attr := range attributes {
+		if attr == nil {
+			continue
+		}
+		if s, ok := attr.Value.(string); ok {
+			pname := "atk_tmp_" + attr.Key
+			setObjText(pname, s)
+			list = append(list, fmt.Sprintf("-%v $%v", attr.Key, pname))
+			continue
+		}
+		list = append(list, fmt.Sprintf("-%v {%v}", attr.Key, attr.Value))
+	}
+	return strings.Join(list, " ")
+}
+
+// embed widget at index, widget should be child of text or the text parent
+func (w *Text) InsertWindow(index TextIndex, widget Widget, attributes ...*TextEmbedAttr) error {
+	if !IsValidWidget(widget) {
+		return ErrInvalid
+	}
+	extra := buildTextEmbedAttributeScript(attributes)
+	return eval(fmt.Sprintf("%v window create {%v} -window %v %v", w.id, index, widget.Id(), extra))
+}
+
+func (w *Text) ConfigureWindow(widget Widget, attributes ...*TextEmbedAttr) error {
+	if !IsValidWidget(widget) {
+		return ErrInvalid
+	}
+	extra := buildTextEmbedAttributeScript(attributes)
+	return eval(fmt.Sprintf("%v window configure %v %v", w.id, widget.Id(), extra))
+}
+
+func (w *Text) WindowAlign(widget Widget) TextEmbedAlign {
+	if !IsValidWidget(widget) {
+		return -1
+	}
+	r, err := evalAsString(fmt.Sprintf("%v window cget %v -align", w.id, widget.Id()))
+	return parserTextEmbedAlignResult(r, err)
+}
+
+// index of embedded widget
+func (w *Text) WindowIndex(widget Widget) TextIndex {
+	if !IsValidWidget(widget) {
+		return ""
+	}
+	return w.Index(TextIndex(widget.Id()))
+}
+
+// embedded widgets
+func (w *Text) Windows() (list []Widget) {
+	ids, _ := evalAsStringList(fmt.Sprintf("%v window names", w.id))
+	for _, id := range ids {
+		if widget, ok := LookupWidget(id); ok {
+			list = append(list, widget)
+		}
+	}
+	return
+}
+
+// embed image at index, returns image name in text
+func (w *Text) InsertImage(index TextIndex, image *Image, attributes ...*TextEmbedAttr) (string, error) {
+	if image == nil || !image.IsValid() {
+		return "", ErrInvalid
+	}
+	extra := buildTextEmbedAttributeScript(attributes)
+	return evalAsString(fmt.Sprintf("%v image create {%v} -image %v %v", w.id, index, image.Id(), extra))
+}
+
+func (w *Text) ConfigureImage(name string, attributes ...*TextEmbedAttr) error {
+	if name == "" {
+		return ErrInvalid
+	}
+	extra := buildTextEmbedAttributeScript(attributes)
+	setObjText("atk_tmp_imagename", name)
+	return eval(fmt.Sprintf("%v image configure $atk_tmp_imagename %v", w.id, extra))
+}
+
+func (w *Text) ImageAlign(name string) TextEmbedAlign {
+	setObjText("atk_tmp_imagename", name)
+	r, err := evalAsString(fmt.Sprintf("%v image cget $atk_tmp_imagename -align", w.id))
+	return parserTextEmbedAlignResult(r, err)
+}
+
+// image of embedded image name
+func (w *Text) Image(name string) *Image {
+	setObjText("atk_tmp_imagename", name)
+	r, err := evalAsString(fmt.Sprintf("%v image cget $atk_tmp_imagename -image", w.id))
+	return parserImageResult(r, err)
+}
+
+// index of embedded image name
+func (w *Text) ImageIndex(name string) TextIndex {
+	setObjText("atk_tmp_imagename", name)
+	r, err := evalAsString(fmt.Sprintf("%v index $atk_tmp_imagename", w.id))
+	if err != nil {
+		return ""
+	}
+	return TextIndex(r)
+}
+
+// embedded image names
+func (w *Text) ImageNames() []string {
+	r, _ := evalAsStringList(fmt.Sprintf("%v image names", w.id))
+	return r
+}
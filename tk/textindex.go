@@ -0,0 +1,171 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// text index expression, line.char, end, mark name, @x,y or with modifiers
+type TextIndex string
+
+const (
+	TextIndexStart    TextIndex = "1.0"
+	TextIndexEnd      TextIndex = "end"
+	TextIndexInsert   TextIndex = "insert"
+	TextIndexCurrent  TextIndex = "current"
+	TextIndexSelFirst TextIndex = "sel.first"
+	TextIndexSelLast  TextIndex = "sel.last"
+)
+
+// index of line and char, line start from 1 and char start from 0
+func NewTextIndex(line int, char int) TextIndex {
+	return TextIndex(fmt.Sprintf("%v.%v", line, char))
+}
+
+// index of rune offset from text start
+func NewTextIndexOffset(offset int) TextIndex {
+	return TextIndexStart.Chars(offset)
+}
+
+// index of char covering window position x,y
+func NewTextIndexPos(x int, y int) TextIndex {
+	return TextIndex(fmt.Sprintf("@%v,%v", x, y))
+}
+
+func (t TextIndex) String() string {
+	return string(t)
+}
+
+func (t TextIndex) modifier(n int, unit string) TextIndex {
+	if n >= 0 {
+		return TextIndex(fmt.Sprintf("%v +%v %v", t, n, unit))
+	}
+	return TextIndex(fmt.Sprintf("%v %v %v", t, n, unit))
+}
+
+// move index by n chars, n may be negative
+func (t TextIndex) Chars(n int) TextIndex {
+	return t.modifier(n, "chars")
+}
+
+// move index by n lines, n may be negative
+func (t TextIndex) Lines(n int) TextIndex {
+	return t.modifier(n, "lines")
+}
+
+func (t TextIndex) LineStart() TextIndex {
+	return t + " linestart"
+}
+
+func (t TextIndex) LineEnd() TextIndex {
+	return t + " lineend"
+}
+
+func (t TextIndex) WordStart() TextIndex {
+	return t + " wordstart"
+}
+
+func (t TextIndex) WordEnd() TextIndex {
+	return t + " wordend"
+}
+
+// line and char of index in line.char form, use Text.Index to normalize other forms
+func (t TextIndex) LineChar() (line int, char int, ok bool) {
+	ar := strings.Split(string(t), ".")
+	if len(ar) != 2 {
+		return
+	}
+	var err error
+	line, err = strconv.Atoi(ar[0])
+	if err != nil {
+		return
+	}
+	char, err = strconv.Atoi(ar[1])
+	if err != nil {
+		return
+	}
+	return line, char, true
+}
+
+// normalize index to line.char form
+func (w *Text) Index(index TextIndex) TextIndex {
+	r, err := evalAsString(fmt.Sprintf("%v index {%v}", w.id, index))
+	if err != nil {
+		return ""
+	}
+	return TextIndex(r)
+}
+
+func (w *Text) LineChar(index TextIndex) (line int, char int) {
+	line, char, _ = w.Index(index).LineChar()
+	return
+}
+
+// compare index a and b, return -1 if a before b, 0 if equal, 1 if a after b
+func (w *Text) CompareIndex(a TextIndex, b TextIndex) int {
+	if r, _ := evalAsBool(fmt.Sprintf("%v compare {%v} < {%v}", w.id, a, b)); r {
+		return -1
+	}
+	if r, _ := evalAsBool(fmt.Sprintf("%v compare {%v} > {%v}", w.id, a, b)); r {
+		return 1
+	}
+	return 0
+}
+
+// offset of index from text start, embedded windows and images count as one char,
+// OffsetToIndex is the inverse
+func (w *Text) IndexToOffset(index TextIndex) int {
+	r, _ := evalAsInt(fmt.Sprintf("%v count -indices 1.0 {%v}", w.id, index))
+	return r
+}
+
+// rune offset of index in text returned by Get, embedded windows and images are not counted
+func (w *Text) charOffset(index TextIndex) int {
+	r, _ := evalAsInt(fmt.Sprintf("%v count -chars 1.0 {%v}", w.id, index))
+	return r
+}
+
+// index of rune offset in text returned by Get, embedded windows and images are skipped
+func (w *Text) charIndex(offset int) TextIndex {
+	return w.Index(TextIndex(fmt.Sprintf("1.0 + %v any chars", offset)))
+}
+
+// normalized index of offset from text start, embedded windows and images count as one char
+func (w *Text) OffsetToIndex(offset int) TextIndex {
+	return w.Index(NewTextIndexOffset(offset))
+}
+
+// text in range start,end; end is optional and empty means one char
+func (w *Text) Get(start TextIndex, end TextIndex) string {
+	r, _ := evalAsString(fmt.Sprintf("%v get %v", w.id, textRangeScript(start, end)))
+	return r
+}
+
+// delete text in range start,end; end is optional and empty means one char
+func (w *Text) Delete(start TextIndex, end TextIndex) error {
+	return eval(fmt.Sprintf("%v delete %v", w.id, textRangeScript(start, end)))
+}
+
+// replace text in range start,end, marks in the range move to start
+func (w *Text) Replace(start TextIndex, end TextIndex, text string) error {
+	setObjText("atk_text_insert", text)
+	return eval(fmt.Sprintf("%v replace {%v} {%v} $atk_text_insert", w.id, start, end))
+}
+
+// scroll the view to make index visible
+func (w *Text) See(index TextIndex) error {
+	return eval(fmt.Sprintf("%v see {%v}", w.id, index))
+}
+
+// move insert cursor to index
+func (w *Text) SetInsertCursor(index TextIndex) error {
+	return eval(fmt.Sprintf("%v mark set insert {%v}", w.id, index))
+}
+
+// insert cursor index in line.char form
+func (w *Text) InsertCursor() TextIndex {
+	return w.Index(TextIndexInsert)
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+)
+
+type TextMarkGravity int
+
+const (
+	TextMarkGravityRight TextMarkGravity = iota
+	TextMarkGravityLeft
+)
+
+var (
+	textMarkGravityName = []string{"right", "left"}
+)
+
+func (v TextMarkGravity) String() string {
+	if v >= 0 && int(v) < len(textMarkGravityName) {
+		return textMarkGravityName[v]
+	}
+	return ""
+}
+
+func parserTextMarkGravityResult(r string, err error) TextMarkGravity {
+	if err != nil {
+		return -1
+	}
+	for n, s := range textMarkGravityName {
+		if s == r {
+			return TextMarkGravity(n)
+		}
+	}
+	return -1
+}
+
+// text mark, a named position between two chars that floats with edits
+type TextMark struct {
+	text *Text
+	name string
+}
+
+func (m *TextMark) Name() string {
+	return m.name
+}
+
+func (m *TextMark) Text() *Text {
+	return m.text
+}
+
+func (m *TextMark) String() string {
+	return fmt.Sprintf("TextMark{%v}", m.name)
+}
+
+func (m *TextMark) IsValid() bool {
+	if m == nil || m.text == nil {
+		return false
+	}
+	for _, name := range m.text.MarkNames() {
+		if name == m.name {
+			return true
+		}
+	}
+	return false
+}
+
+// mark as index expression, use with modifiers e.g. mark.TextIndex().Chars(1)
+func (m *TextMark) TextIndex() TextIndex {
+	return TextIndex(m.name)
+}
+
+// mark index in line.char form
+func (m *TextMark) Index() TextIndex {
+	return m.text.Index(m.TextIndex())
+}
+
+func (m *TextMark) SetIndex(index TextIndex) error {
+	setObjText("atk_tmp_mark", m.name)
+	return eval(fmt.Sprintf("%v mark set $atk_tmp_mark {%v}", m.text.id, index))
+}
+
+// gravity decides which side the mark sticks to when text inserted at mark, default right
+func (m *TextMark) SetGravity(gravity TextMarkGravity) error {
+	setObjText("atk_tmp_mark", m.name)
+	return eval(fmt.Sprintf("%v mark gravity $atk_tmp_mark %v", m.text.id, gravity))
+}
+
+func (m *TextMark) Gravity() TextMarkGravity {
+	setObjText("atk_tmp_mark", m.name)
+	r, err := evalAsString(fmt.Sprintf("%v mark gravity $atk_tmp_mark", m.text.id))
+	return parserTextMarkGravityResult(r, err)
+}
+
+// next mark after this mark, nil if none
+func (m *TextMark) Next() *TextMark {
+	return m.text.NextMark(m.TextIndex())
+}
+
+// previous mark before this mark, nil if none
+func (m *TextMark) Previous() *TextMark {
+	return m.text.PreviousMark(m.TextIndex())
+}
+
+func (m *TextMark) Delete() error {
+	setObjText("atk_tmp_mark", m.name)
+	return eval(fmt.Sprintf("%v mark unset $atk_tmp_mark", m.text.id))
+}
+
+// set mark at index, create mark if not exist
+func (w *Text) SetMark(name string, index TextIndex) *TextMark {
+	if name == "" {
+		return nil
+	}
+	m := &TextMark{w, name}
+	if m.SetIndex(index) != nil {
+		return nil
+	}
+	return m
+}
+
+// mark handle by name, nil if mark not exist
+func (w *Text) Mark(name string) *TextMark {
+	m := &TextMark{w, name}
+	if !m.IsValid() {
+		return nil
+	}
+	return m
+}
+
+func (w *Text) MarkNames() []string {
+	r, _ := evalAsStringList(fmt.Sprintf("%v mark names", w.id))
+	return r
+}
+
+func (w *Text) Marks() (list []*TextMark) {
+	for _, name := range w.MarkNames() {
+		list = append(list, &TextMark{w, name})
+	}
+	return
+}
+
+// first mark at or after index, nil if none
+func (w *Text) NextMark(index TextIndex) *TextMark {
+	r, err := evalAsString(fmt.Sprintf("%v mark next {%v}", w.id, index))
+	if err != nil || r == "" {
+		return nil
+	}
+	return &TextMark{w, r}
+}
+
+// first mark before index, nil if none
+func (w *Text) PreviousMark(index TextIndex) *TextMark {
+	r, err := evalAsString(fmt.Sprintf("%v mark previous {%v}", w.id, index))
+	if err != nil || r == "" {
+		return nil
+	}
+	return &TextMark{w, r}
+}
+
+func (w *Text) DeleteMark(name string) error {
+	if name == "" {
+		return ErrInvalid
+	}
+	return (&TextMark{w, name}).Delete()
+}
@@ -0,0 +1,273 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// text search attribute
+type TextSearchAttr struct {
+	Key   string
+	Value interface{}
+}
+
+// search backwards from index, default forwards
+func TextSearchAttrBackwards() *TextSearchAttr {
+	return &TextSearchAttr{"backwards", nil}
+}
+
+// pattern is regular expression, default exact string
+func TextSearchAttrRegexp() *TextSearchAttr {
+	return &TextSearchAttr{"regexp", nil}
+}
+
+func TextSearchAttrNoCase() *TextSearchAttr {
+	return &TextSearchAttr{"nocase", nil}
+}
+
+// find all matches, default first match only
+func TextSearchAttrAll() *TextSearchAttr {
+	return &TextSearchAttr{"all", nil}
+}
+
+// with all, allow matches overlap
+func TextSearchAttrOverlap() *TextSearchAttr {
+	return &TextSearchAttr{"overlap", nil}
+}
+
+// with regexp, allow match across lines
+func TextSearchAttrNoLineStop() *TextSearchAttr {
+	return &TextSearchAttr{"nolinestop", nil}
+}
+
+// search elided text
+func TextSearchAttrElide() *TextSearchAttr {
+	return &TextSearchAttr{"elide", nil}
+}
+
+// stop search at index, default search wraps around the text
+func TextSearchAttrStopIndex(index TextIndex) *TextSearchAttr {
+	return &TextSearchAttr{"stopindex", index}
+}
+
+type textSearchOption struct {
+	backwards bool
+	regexp    bool
+	nocase    bool
+	all       bool
+	stop      TextIndex
+	switches  []string
+}
+
+func parserTextSearchAttributes(attributes []*TextSearchAttr) (opt textSearchOption) {
+	for _, attr := range attributes {
+		if attr == nil {
+			continue
+		}
+		switch attr.Key {
+		case "stopindex":
+			opt.stop, _ = attr.Value.(TextIndex)
+			continue
+		case "backwards":
+			opt.backwards = true
+		case "regexp":
+			opt.regexp = true
+		case "nocase":
+			opt.nocase = true
+		case "all":
+			opt.all = true
+		}
+		opt.switches = append(opt.switches, "-"+attr.Key)
+	}
+	return
+}
+
+// search pattern from index start, returns match ranges in search order.
+// regexp pattern not accepted by Tcl regular expressions falls back to Go regexp
+// (overlap and nolinestop are ignored in the fallback), other errors are returned.
+func (w *Text) Search(pattern string, start TextIndex, attributes ...*TextSearchAttr) ([]TextRange, error) {
+	if pattern == "" {
+		return nil, ErrInvalid
+	}
+	opt := parserTextSearchAttributes(attributes)
+	setObjText("atk_tmp_pattern", pattern)
+	if opt.regexp && eval("regexp -about -- $atk_tmp_pattern") != nil {
+		return w.searchGoRegexp(pattern, start, opt)
+	}
+	script := fmt.Sprintf("%v search -count atk_tmp_count %v -- $atk_tmp_pattern {%v}", w.id, strings.Join(opt.switches, " "), start)
+	if opt.stop != "" {
+		script += fmt.Sprintf(" {%v}", opt.stop)
+	}
+	r, err := evalAsStringList(script)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, nil
+	}
+	counts, err := evalAsIntList("set atk_tmp_count")
+	if err != nil || len(counts) != len(r) {
+		return nil, ErrInvalid
+	}
+	var list []TextRange
+	for i, index := range r {
+		list = append(list, TextRange{TextIndex(index), w.Index(TextIndex(index).Chars(counts[i]))})
+	}
+	return list, nil
+}
+
+// first match of pattern from index start
+func (w *Text) SearchFirst(pattern string, start TextIndex, attributes ...*TextSearchAttr) (TextRange, bool) {
+	list, err := w.Search(pattern, start, attributes...)
+	if err != nil || len(list) == 0 {
+		return TextRange{}, false
+	}
+	return list[0], true
+}
+
+type textRuneRange struct {
+	start int
+	end   int
+}
+
+func (w *Text) searchGoRegexp(pattern string, start TextIndex, opt textSearchOption) ([]TextRange, error) {
+	if opt.nocase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if err := eval(fmt.Sprintf("%v index {%v}", w.id, start)); err != nil {
+		return nil, err
+	}
+	text := w.Get(TextIndexStart, TextIndexEnd.Chars(-1))
+	pos := w.charOffset(start)
+	stop := -1
+	if opt.stop != "" {
+		if err := eval(fmt.Sprintf("%v index {%v}", w.id, opt.stop)); err != nil {
+			return nil, err
+		}
+		stop = w.charOffset(opt.stop)
+	}
+	// byte offsets to rune offsets
+	var matches []textRuneRange
+	last, runes := 0, 0
+	for _, m := range re.FindAllStringIndex(text, -1) {
+		if m[0] == m[1] {
+			continue
+		}
+		runes += utf8.RuneCountInString(text[last:m[0]])
+		s := runes
+		runes += utf8.RuneCountInString(text[m[0]:m[1]])
+		matches = append(matches, textRuneRange{s, runes})
+		last = m[1]
+	}
+	if opt.backwards {
+		for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+			matches[i], matches[j] = matches[j], matches[i]
+		}
+	}
+	var list []textRuneRange
+	var wrap []textRuneRange
+	for _, m := range matches {
+		if opt.backwards {
+			if m.start < pos {
+				if stop == -1 || m.start >= stop {
+					list = append(list, m)
+				}
+			} else if stop == -1 {
+				wrap = append(wrap, m)
+			}
+		} else {
+			if m.start >= pos {
+				if stop == -1 || m.end <= stop {
+					list = append(list, m)
+				}
+			} else if stop == -1 {
+				wrap = append(wrap, m)
+			}
+		}
+	}
+	list = append(list, wrap...)
+	if !opt.all && len(list) > 1 {
+		list = list[:1]
+	}
+	var ranges []TextRange
+	for _, m := range list {
+		ranges = append(ranges, TextRange{w.charIndex(m.start), w.charIndex(m.end)})
+	}
+	return ranges, nil
+}
+
+// replace all matches of pattern with text, returns number of replacements.
+// with regexp attribute, the pattern must be a Tcl regular expression and
+// \1 or & in text expands to submatch by Tcl regsub on the line of the match,
+// so search and replace use the same regexp engine.
+func (w *Text) ReplaceAll(pattern string, text string, attributes ...*TextSearchAttr) (int, error) {
+	var attrs []*TextSearchAttr
+	opt := parserTextSearchAttributes(attributes)
+	nolinestop := false
+	for _, attr := range attributes {
+		if attr != nil && attr.Key != "backwards" && attr.Key != "all" && attr.Key != "stopindex" && attr.Key != "overlap" {
+			attrs = append(attrs, attr)
+			if attr.Key == "nolinestop" {
+				nolinestop = true
+			}
+		}
+	}
+	var regsub string
+	if opt.regexp {
+		if pattern == "" {
+			return 0, ErrInvalid
+		}
+		switches := "-line"
+		if nolinestop {
+			switches = "-lineanchor"
+		}
+		if opt.nocase {
+			switches += " -nocase"
+		}
+		setObjText("atk_tmp_pattern", pattern)
+		// check pattern by Tcl, Search falls back to Go regexp
+		if err := eval(fmt.Sprintf("regexp %v -about -- $atk_tmp_pattern", switches)); err != nil {
+			return 0, err
+		}
+		regsub = switches
+	}
+	attrs = append(attrs, TextSearchAttrAll(), TextSearchAttrStopIndex(TextIndexEnd))
+	list, err := w.Search(pattern, TextIndexStart, attrs...)
+	if err != nil {
+		return 0, err
+	}
+	// replace from last match to keep index of previous matches
+	for i := len(list) - 1; i >= 0; i-- {
+		repl := text
+		if opt.regexp {
+			repl, err = w.regsubMatch(list[i], pattern, text, regsub)
+			if err != nil {
+				return len(list) - 1 - i, err
+			}
+		}
+		err = w.Replace(list[i].Start, list[i].End, repl)
+		if err != nil {
+			return len(list) - 1 - i, err
+		}
+	}
+	return len(list), nil
+}
+
+// substitution of match by regsub on lines of match, text around the match keeps regexp anchors and lookarounds
+func (w *Text) regsubMatch(r TextRange, pattern string, text string, switches string) (string, error) {
+	setObjText("atk_tmp_pattern", pattern)
+	setObjText("atk_tmp_text", text)
+	return evalAsString(fmt.Sprintf(`set atk_tmp_line [%[1]v get {%[2]v linestart} {%[3]v lineend}]
+set atk_tmp_start [%[1]v count -chars {%[2]v linestart} {%[2]v}]
+set atk_tmp_tail [%[1]v count -chars {%[3]v} {%[3]v lineend}]
+regsub %[4]v -start $atk_tmp_start -- $atk_tmp_pattern $atk_tmp_line $atk_tmp_text atk_tmp_line
+string range $atk_tmp_line $atk_tmp_start end-$atk_tmp_tail`, w.id, r.Start, r.End, switches))
+}
@@ -0,0 +1,344 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// text tag attribute
+type TextTagAttr struct {
+	Key   string
+	Value interface{}
+}
+
+func buildTextTagAttributeScript(attributes []*TextTagAttr) string {
+	var list []string
+	for _, attr := range attributes {
+		if attr == nil {
+			continue
+		}
+		if s, ok := attr.Value.(string); ok {
+			pname := "atk_tmp_" + attr.Key
+			setObjText(pname, s)
+			list = append(list, fmt.Sprintf("-%v $%v", attr.Key, pname))
+			continue
+		}
+		list = append(list, fmt.Sprintf("-%v {%v}", attr.Key, attr.Value))
+	}
+	return strings.Join(list, " ")
+}
+
+type TextRange struct {
+	Start TextIndex
+	End   TextIndex
+}
+
+func textRangeScript(start TextIndex, end TextIndex) string {
+	if end == "" {
+		return fmt.Sprintf("{%v}", start)
+	}
+	return fmt.Sprintf("{%v} {%v}", start, end)
+}
+
+func parserTextRangeList(r []string, err error) (list []TextRange) {
+	if err != nil {
+		return
+	}
+	for i := 0; i+1 < len(r); i += 2 {
+		list = append(list, TextRange{TextIndex(r[i]), TextIndex(r[i+1])})
+	}
+	return
+}
+
+type TextTag struct {
+	text *Text
+	name string
+}
+
+func (t *TextTag) Name() string {
+	return t.name
+}
+
+func (t *TextTag) Text() *Text {
+	return t.text
+}
+
+func (t *TextTag) String() string {
+	return fmt.Sprintf("TextTag{%v}", t.name)
+}
+
+func (t *TextTag) IsValid() bool {
+	if t == nil || t.text == nil {
+		return false
+	}
+	for _, name := range t.text.TagNames("") {
+		if name == t.name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TextTag) Configure(attributes ...*TextTagAttr) error {
+	extra := buildTextTagAttributeScript(attributes)
+	setObjText("atk_tmp_tag", t.name)
+	return eval(fmt.Sprintf("%v tag configure $atk_tmp_tag %v", t.text.id, extra))
+}
+
+func (t *TextTag) NativeAttribute(key string) string {
+	setObjText("atk_tmp_tag", t.name)
+	r, _ := evalAsString(fmt.Sprintf("%v tag cget $atk_tmp_tag -%v", t.text.id, key))
+	return r
+}
+
+func (t *TextTag) SetNativeAttribute(key string, value string) error {
+	return t.Configure(&TextTagAttr{key, value})
+}
+
+// add tag to range start,end; end is optional and empty means one char
+func (t *TextTag) AddRange(start TextIndex, end TextIndex) error {
+	setObjText("atk_tmp_tag", t.name)
+	return eval(fmt.Sprintf("%v tag add $atk_tmp_tag %v", t.text.id, textRangeScript(start, end)))
+}
+
+func (t *TextTag) RemoveRange(start TextIndex, end TextIndex) error {
+	setObjText("atk_tmp_tag", t.name)
+	return eval(fmt.Sprintf("%v tag remove $atk_tmp_tag %v", t.text.id, textRangeScript(start, end)))
+}
+
+func (t *TextTag) Clear() error {
+	return t.RemoveRange(TextIndexStart, TextIndexEnd)
+}
+
+func (t *TextTag) Ranges() []TextRange {
+	setObjText("atk_tmp_tag", t.name)
+	return parserTextRangeList(evalAsStringList(fmt.Sprintf("%v tag ranges $atk_tmp_tag", t.text.id)))
+}
+
+// first range of tag after index start and before end, end is optional
+func (t *TextTag) NextRange(start TextIndex, end TextIndex) (TextRange, bool) {
+	setObjText("atk_tmp_tag", t.name)
+	r := parserTextRangeList(evalAsStringList(fmt.Sprintf("%v tag nextrange $atk_tmp_tag %v", t.text.id, textRangeScript(start, end))))
+	if len(r) == 0 {
+		return TextRange{}, false
+	}
+	return r[0], true
+}
+
+// last range of tag before index start and after end, end is optional
+func (t *TextTag) PrevRange(start TextIndex, end TextIndex) (TextRange, bool) {
+	setObjText("atk_tmp_tag", t.name)
+	r := parserTextRangeList(evalAsStringList(fmt.Sprintf("%v tag prevrange $atk_tmp_tag %v", t.text.id, textRangeScript(start, end))))
+	if len(r) == 0 {
+		return TextRange{}, false
+	}
+	return r[0], true
+}
+
+// raise tag priority above tag, nil for highest priority
+func (t *TextTag) Raise(above *TextTag) error {
+	setObjText("atk_tmp_tag", t.name)
+	script := fmt.Sprintf("%v tag raise $atk_tmp_tag", t.text.id)
+	if above != nil {
+		setObjText("atk_tmp_tag_above", above.name)
+		script += " $atk_tmp_tag_above"
+	}
+	return eval(script)
+}
+
+// lower tag priority below tag, nil for lowest priority
+func (t *TextTag) Lower(below *TextTag) error {
+	setObjText("atk_tmp_tag", t.name)
+	script := fmt.Sprintf("%v tag lower $atk_tmp_tag", t.text.id)
+	if below != nil {
+		setObjText("atk_tmp_tag_below", below.name)
+		script += " $atk_tmp_tag_below"
+	}
+	return eval(script)
+}
+
+// delete tag, ranges and bindings
+func (t *TextTag) Delete() error {
+	setObjText("atk_tmp_tag", t.name)
+	return eval(fmt.Sprintf("%v tag delete $atk_tmp_tag", t.text.id))
+}
+
+// add bind event for text in tag ranges, event is one of Enter, Leave,
+// ButtonPress, Motion, ButtonRelease, KeyPress, KeyRelease or virtual events,
+// returns binding handle for unbind
+func (t *TextTag) BindEvent(event string, fn func(e *Event)) (*Binding, error) {
+	if !IsEvent(event) || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := newItemBinding(t.text.id, "tag bind", t.name)
+	err := b.add(event, fn)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (t *TextTag) ClearBind(event string) error {
+	if !IsEvent(event) {
+		return ErrInvalid
+	}
+	setObjText("atk_tmp_tag", t.name)
+	return eval(fmt.Sprintf("%v tag bind $atk_tmp_tag %v {}", t.text.id, event))
+}
+
+func (t *TextTag) BindInfo() []string {
+	setObjText("atk_tmp_tag", t.name)
+	r, _ := evalAsStringList(fmt.Sprintf("%v tag bind $atk_tmp_tag", t.text.id))
+	return r
+}
+
+// create or configure tag
+func (w *Text) CreateTag(name string, attributes ...*TextTagAttr) *TextTag {
+	if name == "" {
+		return nil
+	}
+	t := &TextTag{w, name}
+	if t.Configure(attributes...) != nil {
+		return nil
+	}
+	return t
+}
+
+// tag handle by name, tag exists in the text after configure or add range
+func (w *Text) Tag(name string) *TextTag {
+	if name == "" {
+		return nil
+	}
+	return &TextTag{w, name}
+}
+
+func (w *Text) Tags() (list []*TextTag) {
+	for _, name := range w.TagNames("") {
+		list = append(list, &TextTag{w, name})
+	}
+	return
+}
+
+// tag names at index in priority order (lowest first), empty index for all tags
+func (w *Text) TagNames(index TextIndex) []string {
+	script := fmt.Sprintf("%v tag names", w.id)
+	if index != "" {
+		script += fmt.Sprintf(" {%v}", index)
+	}
+	r, _ := evalAsStringList(script)
+	return r
+}
+
+func (w *Text) AddTag(name string, start TextIndex, end TextIndex) error {
+	if name == "" {
+		return ErrInvalid
+	}
+	return w.Tag(name).AddRange(start, end)
+}
+
+func (w *Text) RemoveTag(name string, start TextIndex, end TextIndex) error {
+	if name == "" {
+		return ErrInvalid
+	}
+	return w.Tag(name).RemoveRange(start, end)
+}
+
+func (w *Text) TagRanges(name string) []TextRange {
+	if name == "" {
+		return nil
+	}
+	return w.Tag(name).Ranges()
+}
+
+func (w *Text) DeleteTag(name string) error {
+	if name == "" {
+		return ErrInvalid
+	}
+	return w.Tag(name).Delete()
+}
+
+// insert text at index with tags
+func (w *Text) InsertTaggedText(index TextIndex, text string, tags ...string) error {
+	setObjText("atk_text_insert", text)
+	setObjTextList("atk_text_tags", tags)
+	return eval(fmt.Sprintf("%v insert {%v} $atk_text_insert $atk_text_tags", w.id, index))
+}
+
+func (w *Text) AppendTaggedText(text string, tags ...string) error {
+	return w.InsertTaggedText(TextIndexEnd, text, tags...)
+}
+
+func TextTagAttrFont(font Font) *TextTagAttr {
+	if font == nil {
+		return nil
+	}
+	return &TextTagAttr{"font", font.Id()}
+}
+
+func TextTagAttrForeground(color string) *TextTagAttr {
+	return &TextTagAttr{"foreground", color}
+}
+
+func TextTagAttrBackground(color string) *TextTagAttr {
+	return &TextTagAttr{"background", color}
+}
+
+func TextTagAttrUnderline(underline bool) *TextTagAttr {
+	return &TextTagAttr{"underline", boolToInt(underline)}
+}
+
+func TextTagAttrOverstrike(overstrike bool) *TextTagAttr {
+	return &TextTagAttr{"overstrike", boolToInt(overstrike)}
+}
+
+func TextTagAttrLineAboveSpace(spacing int) *TextTagAttr {
+	return &TextTagAttr{"spacing1", spacing}
+}
+
+func TextTagAttrLineWrapSpace(spacing int) *TextTagAttr {
+	return &TextTagAttr{"spacing2", spacing}
+}
+
+func TextTagAttrLineBelowSpace(spacing int) *TextTagAttr {
+	return &TextTagAttr{"spacing3", spacing}
+}
+
+func TextTagAttrJustify(justify Justify) *TextTagAttr {
+	return &TextTagAttr{"justify", justify}
+}
+
+// hide text in tag ranges
+func TextTagAttrElide(elide bool) *TextTagAttr {
+	return &TextTagAttr{"elide", boolToInt(elide)}
+}
+
+func TextTagAttrLeftMargin(margin int) *TextTagAttr {
+	return &TextTagAttr{"lmargin1", margin}
+}
+
+func TextTagAttrWrapLeftMargin(margin int) *TextTagAttr {
+	return &TextTagAttr{"lmargin2", margin}
+}
+
+func TextTagAttrRightMargin(margin int) *TextTagAttr {
+	return &TextTagAttr{"rmargin", margin}
+}
+
+// baseline offset, positive for superscript and negative for subscript
+func TextTagAttrOffset(offset int) *TextTagAttr {
+	return &TextTagAttr{"offset", offset}
+}
+
+func TextTagAttrReliefStyle(relief ReliefStyle) *TextTagAttr {
+	return &TextTagAttr{"relief", relief}
+}
+
+func TextTagAttrBorderWidth(width int) *TextTagAttr {
+	return &TextTagAttr{"borderwidth", width}
+}
+
+func TextTagAttrLineWrap(wrap LineWrapMode) *TextTagAttr {
+	return &TextTagAttr{"wrap", wrap}
+}
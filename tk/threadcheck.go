@@ -0,0 +1,81 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"log"
+	"os"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/visualfc/atk/tk/interp"
+)
+
+// check of tk calls made off the main loop thread
+type ThreadCheckMode int32
+
+const (
+	ThreadCheckOff ThreadCheckMode = iota
+	ThreadCheckLog
+	ThreadCheckPanic
+)
+
+var (
+	threadCheckModeName = []string{"off", "log", "panic"}
+)
+
+func (v ThreadCheckMode) String() string {
+	if v >= 0 && int(v) < len(threadCheckModeName) {
+		return threadCheckModeName[v]
+	}
+	return ""
+}
+
+func parserThreadCheckModeResult(r string, err error) ThreadCheckMode {
+	if err != nil {
+		return -1
+	}
+	for n, s := range threadCheckModeName {
+		if s == r {
+			return ThreadCheckMode(n)
+		}
+	}
+	return -1
+}
+
+var (
+	threadCheckMode atomic.Int32
+)
+
+// initial mode from ATK_THREADCHECK environment: off, log or panic
+func init() {
+	if mode := parserThreadCheckModeResult(os.Getenv("ATK_THREADCHECK"), nil); mode != -1 {
+		threadCheckMode.Store(int32(mode))
+	}
+}
+
+// set check mode, while main loop is running every tk call from other goroutines
+// is logged with stack trace or panics, use Async or AsyncWait from goroutines
+func SetThreadCheckMode(mode ThreadCheckMode) {
+	if mode.String() == "" {
+		return
+	}
+	threadCheckMode.Store(int32(mode))
+}
+
+func ThreadCheck() ThreadCheckMode {
+	return ThreadCheckMode(threadCheckMode.Load())
+}
+
+const threadCheckMessage = "tk: call off main thread, use tk.Async"
+
+func checkMainThread() {
+	mode := ThreadCheckMode(threadCheckMode.Load())
+	if mode == ThreadCheckOff || !interp.IsMainLoopRunning() || interp.IsMainThread() {
+		return
+	}
+	if mode == ThreadCheckPanic {
+		panic(threadCheckMessage)
+	}
+	log.Printf("%v\n%s", threadCheckMessage, debug.Stack())
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"testing"
+)
+
+func init() {
+	registerTest("ThreadCheck", testThreadCheck)
+}
+
+func testThreadCheck(t *testing.T) {
+	old := ThreadCheck()
+	defer SetThreadCheckMode(old)
+
+	SetThreadCheckMode(ThreadCheckPanic)
+	if v := ThreadCheck(); v != ThreadCheckPanic {
+		t.Fatal("ThreadCheck", ThreadCheckPanic, v)
+	}
+	// main thread
+	if err := eval("set atk_tmp_check 1"); err != nil {
+		t.Fatal("eval", err)
+	}
+	ch := make(chan interface{})
+	go func() {
+		defer func() {
+			ch <- recover()
+		}()
+		eval("set atk_tmp_check 1")
+	}()
+	if r := <-ch; r == nil {
+		t.Fatal("ThreadCheckPanic")
+	}
+	SetThreadCheckMode(ThreadCheckOff)
+	if v := parserThreadCheckModeResult("log", nil); v != ThreadCheckLog {
+		t.Fatal("parserThreadCheckModeResult", ThreadCheckLog, v)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"time"
+)
+
+// timer on Tcl event loop by after command, fn called on main thread.
+// Timer methods must be called on main thread.
+type Timer struct {
+	id     string
+	action string
+	fn     func()
+	d      time.Duration
+	repeat bool
+	idle   bool
+}
+
+// call fn once after duration d
+func After(d time.Duration, fn func()) *Timer {
+	return newTimer(d, fn, false, false)
+}
+
+// call fn every duration d until stop
+func Every(d time.Duration, fn func()) *Timer {
+	return newTimer(d, fn, true, false)
+}
+
+// call fn once when the event loop is idle
+func Idle(fn func()) *Timer {
+	return newTimer(0, fn, false, true)
+}
+
+func newTimer(d time.Duration, fn func(), repeat bool, idle bool) *Timer {
+	if fn == nil {
+		return nil
+	}
+	t := &Timer{fn: fn, d: d, repeat: repeat, idle: idle}
+	if t.schedule() != nil {
+		return nil
+	}
+	return t
+}
+
+func (t *Timer) schedule() (err error) {
+	if t.action == "" {
+		t.action = makeActionId()
+		mainInterp.CreateAction(t.action, func([]string) {
+			t.fire()
+		})
+	}
+	if t.idle {
+		t.id, err = evalAsString(fmt.Sprintf("after idle %v", t.action))
+	} else {
+		t.id, err = evalAsString(fmt.Sprintf("after %v %v", t.d.Milliseconds(), t.action))
+	}
+	if err != nil {
+		t.id = ""
+		t.release()
+	}
+	return
+}
+
+func (t *Timer) release() {
+	if t.action != "" {
+		eval(fmt.Sprintf("rename %v {}", t.action))
+		t.action = ""
+	}
+}
+
+func (t *Timer) fire() {
+	t.id = ""
+	if t.repeat {
+		t.schedule()
+	} else {
+		t.release()
+	}
+	t.fn()
+}
+
+func (t *Timer) IsActive() bool {
+	return t.id != ""
+}
+
+func (t *Timer) Duration() time.Duration {
+	return t.d
+}
+
+// stop timer, returns false if timer already fired or stopped
+func (t *Timer) Stop() bool {
+	if t.id == "" {
+		return false
+	}
+	eval(fmt.Sprintf("after cancel %v", t.id))
+	t.id = ""
+	t.release()
+	return true
+}
+
+// restart timer with duration d (ignored for idle timer), returns true if timer had been active
+func (t *Timer) Reset(d time.Duration) bool {
+	active := t.Stop()
+	t.d = d
+	t.schedule()
+	return active
+}
+
+// returns function that calls fn after d has elapsed since the last call.
+// call the returned function on main thread.
+func Debounce(d time.Duration, fn func()) func() {
+	var t *Timer
+	return func() {
+		if t == nil {
+			t = After(d, fn)
+		} else {
+			t.Reset(d)
+		}
+	}
+}
+
+// returns function that calls fn at most once per d, the first call runs fn at once
+// and calls during d run fn once at the end of d.
+// call the returned function on main thread.
+func Throttle(d time.Duration, fn func()) func() {
+	var t *Timer
+	pending := false
+	tick := func() {
+		if pending {
+			pending = false
+			fn()
+			t.Reset(d)
+		}
+	}
+	return func() {
+		if t != nil && t.IsActive() {
+			pending = true
+			return
+		}
+		fn()
+		if t == nil {
+			t = After(d, tick)
+		} else {
+			t.Reset(d)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"testing"
+	"time"
+)
+
+func init() {
+	registerTest("Timer", testTimer)
+}
+
+func waitTimer(d time.Duration) {
+	for start := time.Now(); time.Since(start) < d; {
+		Update()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func testTimer(t *testing.T) {
+	n := 0
+	timer := After(10*time.Millisecond, func() { n++ })
+	if timer == nil || !timer.IsActive() {
+		t.Fatal("After")
+	}
+	waitTimer(50 * time.Millisecond)
+	if n != 1 || timer.IsActive() {
+		t.Fatal("After", 1, n)
+	}
+	if timer.Stop() {
+		t.Fatal("Stop")
+	}
+	timer.Reset(10 * time.Millisecond)
+	if !timer.Stop() {
+		t.Fatal("Reset")
+	}
+	waitTimer(30 * time.Millisecond)
+	if n != 1 {
+		t.Fatal("Stop", 1, n)
+	}
+
+	idle := 0
+	Idle(func() { idle++ })
+	Update()
+	if idle != 1 {
+		t.Fatal("Idle", 1, idle)
+	}
+
+	every := 0
+	var tick *Timer
+	tick = Every(5*time.Millisecond, func() {
+		every++
+		if every == 3 {
+			tick.Stop()
+		}
+	})
+	waitTimer(100 * time.Millisecond)
+	if every != 3 {
+		t.Fatal("Every", 3, every)
+	}
+
+	debounce := 0
+	fn := Debounce(20*time.Millisecond, func() { debounce++ })
+	fn()
+	fn()
+	fn()
+	waitTimer(60 * time.Millisecond)
+	if debounce != 1 {
+		t.Fatal("Debounce", 1, debounce)
+	}
+
+	throttle := 0
+	fn = Throttle(20*time.Millisecond, func() { throttle++ })
+	fn()
+	fn()
+	fn()
+	if throttle != 1 {
+		t.Fatal("Throttle", 1, throttle)
+	}
+	waitTimer(60 * time.Millisecond)
+	if throttle != 2 {
+		t.Fatal("Throttle", 2, throttle)
+	}
+}
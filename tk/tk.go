@@ -88,6 +88,12 @@ func TkVersion() (ver string) {
 	return mainInterp.TkVersion()
 }
 
+// windowing system: x11, win32 or aqua
+func WindowingSystem() string {
+	r, _ := evalAsString("tk windowingsystem")
+	return r
+}
+
 func TclLibary() (path string) {
 	path, _ = evalAsString("set tcl_library")
 	return
@@ -119,56 +125,72 @@ func MainLoop(fn func()) error {
 	return nil
 }
 
-func Async(fn func()) {
-	interp.Async(fn)
+// run fn on main thread by event loop, returns ErrClosed and fn is dropped if main loop is not running
+func Async(fn func()) error {
+	if fn == nil {
+		return ErrInvalid
+	}
+	if !interp.Async(fn) {
+		return ErrClosed
+	}
+	return nil
 }
 
 func Update() {
 	eval("update")
 }
 
+// quit main loop, run shutdown hooks and destroy root window
 func Quit() {
-	Async(func() {
-		DestroyWidget(rootWindow)
-	})
+	QuitWithError(nil)
 }
 
 func eval(script string) error {
+	checkMainThread()
 	return mainInterp.Eval(script)
 }
 
 func evalAsString(script string) (string, error) {
+	checkMainThread()
 	return mainInterp.EvalAsString(script)
 }
 
 func evalAsInt(script string) (int, error) {
+	checkMainThread()
 	return mainInterp.EvalAsInt(script)
 }
 
 func evalAsUint(script string) (uint, error) {
+	checkMainThread()
 	return mainInterp.EvalAsUint(script)
 }
 
 func evalAsFloat64(script string) (float64, error) {
+	checkMainThread()
 	return mainInterp.EvalAsFloat64(script)
 }
 
 func evalAsBool(script string) (bool, error) {
+	checkMainThread()
 	return mainInterp.EvalAsBool(script)
 }
 
 func evalAsStringList(script string) ([]string, error) {
+	checkMainThread()
 	return mainInterp.EvalAsStringList(script)
 }
 
 func evalAsIntList(script string) ([]int, error) {
+	checkMainThread()
 	return mainInterp.EvalAsIntList(script)
 }
 
 func setObjText(obj string, text string) {
+	checkMainThread()
 	mainInterp.SetStringVar(obj, text, false)
 }
 
 func setObjTextList(obj string, list []string) {
+	checkMainThread()
 	mainInterp.SetStringList(obj, list, false)
 }
@@ -0,0 +1,351 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// toolbar button style, value is ttk compound
+type ToolBarStyle int
+
+const (
+	ToolBarIconOnly ToolBarStyle = iota
+	ToolBarTextOnly
+	ToolBarTextBesideIcon
+	ToolBarTextUnderIcon
+)
+
+var (
+	toolBarStyleName = []string{"image", "text", "left", "top"}
+)
+
+func (v ToolBarStyle) String() string {
+	if v >= 0 && int(v) < len(toolBarStyleName) {
+		return toolBarStyleName[v]
+	}
+	return ""
+}
+
+// toolbar of actions, buttons overflow into chevron menu when toolbar is too small
+type ToolBar struct {
+	*Frame
+	orient    Orient
+	style     ToolBarStyle
+	items     []*toolBarItem
+	chevron   *MenuButton
+	menu      *Menu
+	overflow  []*toolBarItem
+	layoutKey string
+}
+
+// pack padding of toolbar buttons and separators
+const toolBarItemPad = 1
+
+// toolbar button or separator of action
+type toolBarItem struct {
+	bar    *ToolBar
+	act    *Action
+	widget Widget
+}
+
+func (it *toolBarItem) isValid() bool {
+	return IsValidWidget(it.widget)
+}
+
+func (it *toolBarItem) updateAction(act *Action) {
+	it.configure()
+	it.bar.layout()
+}
+
+func (it *toolBarItem) configure() error {
+	act := it.act
+	image := ""
+	if act.icon != nil {
+		image = act.icon.Id()
+	}
+	state := "normal"
+	if !act.enabled {
+		state = "disabled"
+	}
+	setObjText("atk_tmp_text", act.label)
+	return eval(fmt.Sprintf("%v configure -text $atk_tmp_text -underline {%v} -image {%v} -compound {%v} -state {%v}",
+		it.widget.Id(), act.underline, image, it.bar.style, state))
+}
+
+func NewToolBar(parent Widget, orient Orient, attributes ...*WidgetAttr) *ToolBar {
+	frame := NewFrame(parent, attributes...)
+	if frame == nil {
+		return nil
+	}
+	w := &ToolBar{Frame: frame, orient: orient, style: ToolBarTextBesideIcon}
+	w.chevron = NewMenuButton(frame, "»", WidgetAttrInitUseTheme(true))
+	w.menu = NewMenu(w.chevron, MenuAttrTearoff(false))
+	w.chevron.SetMenu(w.menu)
+	eval(fmt.Sprintf("%v configure -style Toolbutton -takefocus 0", w.chevron.Id()))
+	BindConfigure(frame.Id(), func(e *ConfigureEvent) {
+		if e.Widget != nil && e.Widget.Id() == w.id {
+			w.layout()
+		}
+	})
+	RegisterWidget(w)
+	return w
+}
+
+func (w *ToolBar) SetOrient(orient Orient) error {
+	if orient.String() == "" {
+		return ErrInvalid
+	}
+	w.orient = orient
+	for _, it := range w.items {
+		if it.act.IsSeparator() {
+			eval(fmt.Sprintf("%v configure -orient {%v}", it.widget.Id(), w.separatorOrient()))
+		}
+	}
+	w.layoutKey = ""
+	w.layout()
+	return nil
+}
+
+func (w *ToolBar) Orient() Orient {
+	return w.orient
+}
+
+func (w *ToolBar) SetStyle(style ToolBarStyle) error {
+	if style.String() == "" {
+		return ErrInvalid
+	}
+	w.style = style
+	for _, it := range w.items {
+		if !it.act.IsSeparator() {
+			it.configure()
+		}
+	}
+	w.layoutKey = ""
+	w.layout()
+	return nil
+}
+
+func (w *ToolBar) Style() ToolBarStyle {
+	return w.style
+}
+
+func (w *ToolBar) separatorOrient() Orient {
+	if w.orient == Horizontal {
+		return Vertical
+	}
+	return Horizontal
+}
+
+func (w *ToolBar) AddAction(act *Action) error {
+	return w.InsertAction(-1, act)
+}
+
+func (w *ToolBar) AddActions(actions []*Action) {
+	for _, act := range actions {
+		w.AddAction(act)
+	}
+}
+
+func (w *ToolBar) AddSeparator() error {
+	return w.AddAction(NewSeparatorAction())
+}
+
+// insert action button at index, check and radio actions share state with menus
+func (w *ToolBar) InsertAction(index int, act *Action) error {
+	if act == nil {
+		return ErrInvalid
+	}
+	if !act.IsSeparator() && w.findItem(act) != -1 {
+		return ErrExist
+	}
+	it := &toolBarItem{bar: w, act: act}
+	theme := WidgetAttrInitUseTheme(true)
+	var script string
+	if act.IsSeparator() {
+		it.widget = NewSeparator(w.Frame, w.separatorOrient())
+	} else if act.IsRadioAction() {
+		it.widget = NewRadioButton(w.Frame, act.label, theme)
+		script = fmt.Sprintf("-variable {%v} -value {%v}", act.groupid, act.radioid)
+	} else if act.IsCheckAction() {
+		it.widget = NewCheckButton(w.Frame, act.label, theme)
+		script = fmt.Sprintf("-variable {%v}", act.checkid)
+	} else {
+		it.widget = NewButton(w.Frame, act.label, theme)
+	}
+	if IsNilInterface(it.widget) {
+		return ErrInvalid
+	}
+	if !act.IsSeparator() {
+		err := eval(fmt.Sprintf("%v configure -style Toolbutton -takefocus 0 -command {%v} %v", it.widget.Id(), act.actid, script))
+		if err != nil {
+			it.widget.Destroy()
+			return err
+		}
+		it.configure()
+		bindToolTip(it.widget, func() string {
+			if it.act.shortcut.IsValid() {
+				return fmt.Sprintf("%v (%v)", it.act.tooltip, it.act.shortcut.Text())
+			}
+			return it.act.tooltip
+		})
+		act.addView(it)
+	}
+	if index < 0 || index >= len(w.items) {
+		w.items = append(w.items, it)
+	} else {
+		w.items = append(w.items[:index], append([]*toolBarItem{it}, w.items[index:]...)...)
+	}
+	w.layout()
+	return nil
+}
+
+func (w *ToolBar) findItem(act *Action) int {
+	for n, it := range w.items {
+		if it.act == act {
+			return n
+		}
+	}
+	return -1
+}
+
+func (w *ToolBar) RemoveAction(act *Action) error {
+	n := w.findItem(act)
+	if n == -1 {
+		return ErrNotExist
+	}
+	it := w.items[n]
+	w.items = append(w.items[:n], w.items[n+1:]...)
+	act.removeView(it)
+	w.menu.detachAction(act)
+	it.widget.Destroy()
+	w.layout()
+	return nil
+}
+
+func (w *ToolBar) Actions() (list []*Action) {
+	for _, it := range w.items {
+		list = append(list, it.act)
+	}
+	return
+}
+
+// button widget of action
+func (w *ToolBar) ActionWidget(act *Action) Widget {
+	if n := w.findItem(act); n != -1 {
+		return w.items[n].widget
+	}
+	return nil
+}
+
+// actions in chevron menu
+func (w *ToolBar) OverflowActions() (list []*Action) {
+	for _, it := range w.overflow {
+		list = append(list, it.act)
+	}
+	return
+}
+
+func (w *ToolBar) layout() {
+	if !IsValidWidget(w.Frame) {
+		return
+	}
+	sizeCmd := "width"
+	if w.orient == Vertical {
+		sizeCmd = "height"
+	}
+	size, _ := evalAsInt(fmt.Sprintf("winfo %v %v", sizeCmd, w.id))
+	w.layoutWithSize(size)
+}
+
+// pack buttons fit in size, size <= 1 for toolbar is not mapped
+func (w *ToolBar) layoutWithSize(size int) {
+	sizeCmd, side, chevronSide := "reqwidth", "left", "right"
+	if w.orient == Vertical {
+		sizeCmd, side, chevronSide = "reqheight", "top", "bottom"
+	}
+	var visible []*toolBarItem
+	for _, it := range w.items {
+		if it.act.visible && it.isValid() {
+			visible = append(visible, it)
+		}
+	}
+	var ids []string
+	var script []string
+	for _, it := range visible {
+		ids = append(ids, it.widget.Id())
+		script = append(script, fmt.Sprintf("[winfo %v %v]", sizeCmd, it.widget.Id()))
+	}
+	sizes, _ := evalAsIntList("list " + strings.Join(script, " "))
+	fits := len(visible)
+	if size > 1 && len(sizes) == len(visible) {
+		// buttons are packed with padding on both sides
+		for i := range sizes {
+			sizes[i] += 2 * toolBarItemPad
+		}
+		total := 0
+		for _, v := range sizes {
+			total += v
+		}
+		if total > size {
+			chevron, _ := evalAsInt(fmt.Sprintf("winfo %v %v", sizeCmd, w.chevron.Id()))
+			used := 0
+			fits = 0
+			for _, v := range sizes {
+				if used+v > size-chevron {
+					break
+				}
+				used += v
+				fits++
+			}
+		}
+	}
+	key := fmt.Sprintf("%v %v %v", w.orient, fits, strings.Join(ids, " "))
+	if key == w.layoutKey {
+		return
+	}
+	w.layoutKey = key
+
+	var forget []string
+	for _, it := range w.items {
+		if it.isValid() {
+			forget = append(forget, it.widget.Id())
+		}
+	}
+	forget = append(forget, w.chevron.Id())
+	cmds := []string{"pack forget " + strings.Join(forget, " ")}
+	fill := "y"
+	if w.orient == Vertical {
+		fill = "x"
+	}
+	for _, it := range visible[:fits] {
+		cmds = append(cmds, fmt.Sprintf("pack %v -side %v -fill %v -padx %v -pady %v", it.widget.Id(), side, fill, toolBarItemPad, toolBarItemPad))
+	}
+	if fits < len(visible) {
+		cmds = append(cmds, fmt.Sprintf("pack %v -side %v", w.chevron.Id(), chevronSide))
+	}
+	eval(strings.Join(cmds, "\n"))
+
+	// rebuild chevron menu
+	for _, it := range w.overflow {
+		w.menu.detachAction(it.act)
+	}
+	eval(fmt.Sprintf("%v delete 0 end", w.menu.Id()))
+	w.overflow = visible[fits:]
+	// separators between actions only
+	separator := false
+	count := 0
+	for _, it := range w.overflow {
+		if it.act.IsSeparator() {
+			separator = count > 0
+			continue
+		}
+		if separator {
+			w.menu.AddSeparator()
+			separator = false
+		}
+		w.menu.AddAction(it.act)
+		count++
+	}
+}
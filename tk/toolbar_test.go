@@ -0,0 +1,108 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	registerTest("ToolBar", testToolBar)
+}
+
+func testToolBar(t *testing.T) {
+	w := NewToolBar(nil, Horizontal)
+	defer w.Destroy()
+	if v := FindWidget(w.Id()); v != Widget(w) {
+		t.Fatal("FindWidget", v)
+	}
+
+	open := NewAction("Open")
+	save := NewAction("Save")
+	wrap := NewCheckAction("Wrap")
+	group := NewActionGroup()
+	left := group.AddNewRadioAction("Left")
+	right := group.AddNewRadioAction("Right")
+	w.AddActions([]*Action{open, save, NewSeparatorAction(), wrap, left, right})
+	if v := len(w.Actions()); v != 6 {
+		t.Fatal("Actions", 6, v)
+	}
+	if err := w.AddAction(open); err != ErrExist {
+		t.Fatal("AddAction", err)
+	}
+
+	cget := func(act *Action, option string) string {
+		r, _ := evalAsString(fmt.Sprintf("%v cget -%v", w.ActionWidget(act).Id(), option))
+		return r
+	}
+	save.SetEnabled(false)
+	if v := cget(save, "state"); v != "disabled" {
+		t.Fatal("SetEnabled", "disabled", v)
+	}
+	save.SetLabel("Save All")
+	if v := cget(save, "text"); v != "Save All" {
+		t.Fatal("SetLabel", "Save All", v)
+	}
+	w.SetStyle(ToolBarTextOnly)
+	if v := cget(open, "compound"); v != "text" {
+		t.Fatal("SetStyle", "text", v)
+	}
+
+	// shared state with menu
+	menu := NewMenu(nil)
+	defer menu.Destroy()
+	menu.AddAction(wrap)
+	if v := cget(wrap, "variable"); v != wrap.checkid {
+		t.Fatal("check variable", wrap.checkid, v)
+	}
+	wrap.SetChecked(true)
+	if v, _ := evalAsString(fmt.Sprintf("set [%v cget -variable]", w.ActionWidget(wrap).Id())); v != "1" {
+		t.Fatal("check state", "1", v)
+	}
+	right.SetChecked(true)
+	if v, _ := evalAsString(fmt.Sprintf("set [%v cget -variable]", w.ActionWidget(left).Id())); v != right.radioid {
+		t.Fatal("radio state", right.radioid, v)
+	}
+
+	// overflow
+	w.layoutKey = ""
+	w.layoutWithSize(1)
+	if v := len(w.OverflowActions()); v != 0 {
+		t.Fatal("OverflowActions", 0, v)
+	}
+	w.layoutWithSize(2)
+	if v := len(w.OverflowActions()); v != 6 {
+		t.Fatal("OverflowActions", 6, v)
+	}
+	if v := menuLastIndex(w.menu.Id()); v != 5 {
+		t.Fatal("overflow menu", 5, v)
+	}
+	// buttons fit in size with padding, others overflow
+	reqwidth := func(id string) int {
+		r, _ := evalAsInt(fmt.Sprintf("winfo reqwidth %v", id))
+		return r
+	}
+	size := reqwidth(w.ActionWidget(open).Id()) + reqwidth(w.ActionWidget(save).Id()) + 4*toolBarItemPad + reqwidth(w.chevron.Id())
+	w.layoutWithSize(size)
+	if v := len(w.OverflowActions()); v != 4 {
+		t.Fatal("OverflowActions", 4, v)
+	}
+	w.layoutWithSize(size - 1)
+	if v := len(w.OverflowActions()); v != 5 {
+		t.Fatal("OverflowActions", 5, v)
+	}
+	save.SetVisible(false)
+	w.layoutWithSize(2)
+	if v := len(w.OverflowActions()); v != 5 {
+		t.Fatal("SetVisible", 5, v)
+	}
+	w.RemoveAction(open)
+	if w.ActionWidget(open) != nil || len(w.Actions()) != 5 {
+		t.Fatal("RemoveAction", w.Actions())
+	}
+	w.SetOrient(Vertical)
+	if w.Orient() != Vertical {
+		t.Fatal("SetOrient")
+	}
+}
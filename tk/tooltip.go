@@ -0,0 +1,66 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	toolTipId    = ".atk_tooltip"
+	toolTipDelay = 600 * time.Millisecond
+)
+
+var (
+	toolTipTimer *Timer
+)
+
+// show tooltip text for widget after mouse hover delay, text is called on show
+func bindToolTip(w Widget, text func() string) error {
+	_, err := BindCrossing(w.Id(), func(e *CrossingEvent) {
+		hideToolTip()
+		if e.Enter {
+			toolTipTimer = After(toolTipDelay, func() {
+				toolTipTimer = nil
+				if IsValidWidget(w) {
+					showToolTip(text())
+				}
+			})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	_, err = BindEvent(w.Id(), "<ButtonPress>", func(e *Event) {
+		hideToolTip()
+	})
+	return err
+}
+
+// show tooltip at mouse pointer
+func showToolTip(text string) error {
+	if text == "" {
+		return nil
+	}
+	setObjText("atk_tmp_text", text)
+	return eval(fmt.Sprintf(`if {![winfo exists %[1]v]} {
+	toplevel %[1]v
+	wm withdraw %[1]v
+	wm overrideredirect %[1]v 1
+	label %[1]v.label -background lightyellow -foreground black -relief solid -borderwidth 1 -justify left -padx 4
+	pack %[1]v.label
+}
+%[1]v.label configure -text $atk_tmp_text
+wm geometry %[1]v +[expr {[winfo pointerx .]+12}]+[expr {[winfo pointery .]+16}]
+wm deiconify %[1]v
+raise %[1]v`, toolTipId))
+}
+
+func hideToolTip() {
+	if toolTipTimer != nil {
+		toolTipTimer.Stop()
+		toolTipTimer = nil
+	}
+	eval(fmt.Sprintf("if {[winfo exists %[1]v]} {wm withdraw %[1]v}", toolTipId))
+}
@@ -0,0 +1,405 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// modifier and button state bits of event %s
+type EventState int
+
+const (
+	EventStateShift   EventState = 1 << 0
+	EventStateLock    EventState = 1 << 1
+	EventStateControl EventState = 1 << 2
+	EventStateMod1    EventState = 1 << 3
+	EventStateMod2    EventState = 1 << 4
+	EventStateMod3    EventState = 1 << 5
+	EventStateMod4    EventState = 1 << 6
+	EventStateMod5    EventState = 1 << 7
+	EventStateButton1 EventState = 1 << 8
+	EventStateButton2 EventState = 1 << 9
+	EventStateButton3 EventState = 1 << 10
+	EventStateButton4 EventState = 1 << 11
+	EventStateButton5 EventState = 1 << 12
+	// alt key on win32
+	EventStateAlt EventState = 1 << 17
+)
+
+func parserEventState(s string) EventState {
+	v, _ := strconv.ParseInt(s, 0, 0)
+	return EventState(v)
+}
+
+// key modifiers of state, alt and meta bits depend on windowing system:
+// x11 Mod1 is Alt and Mod4 is Meta, aqua Mod1 is Command (Meta) and Mod2 is Option (Alt)
+func (s EventState) KeyModifier() KeyModifier {
+	var mod KeyModifier
+	if s&EventStateShift != 0 {
+		mod |= KeyModifierShift
+	}
+	if s&EventStateControl != 0 {
+		mod |= KeyModifierControl
+	}
+	switch eventWindowingSystem() {
+	case "aqua":
+		if s&EventStateMod1 != 0 {
+			mod |= KeyModifierMeta
+		}
+		if s&EventStateMod2 != 0 {
+			mod |= KeyModifierAlt
+		}
+	case "win32":
+		if s&EventStateAlt != 0 {
+			mod |= KeyModifierAlt
+		}
+	default:
+		if s&EventStateMod1 != 0 {
+			mod |= KeyModifierAlt
+		}
+		if s&EventStateMod4 != 0 {
+			mod |= KeyModifierMeta
+		}
+	}
+	return mod
+}
+
+// mouse button 1-5 is down
+func (s EventState) IsButtonDown(button int) bool {
+	if button < 1 || button > 5 {
+		return false
+	}
+	return s&(EventStateButton1<<uint(button-1)) != 0
+}
+
+func (s EventState) String() string {
+	var ar []string
+	if mod := s.KeyModifier(); mod != 0 {
+		ar = append(ar, mod.String())
+	}
+	for i := 1; i <= 5; i++ {
+		if s.IsButtonDown(i) {
+			ar = append(ar, "Button"+strconv.Itoa(i))
+		}
+	}
+	return strings.Join(ar, " ")
+}
+
+var (
+	eventWindowingSystemName string
+)
+
+func init() {
+	registerInit(func() {
+		eventWindowingSystemName = WindowingSystem()
+	})
+}
+
+func eventWindowingSystem() string {
+	return eventWindowingSystemName
+}
+
+func eventInt(s string) int {
+	v, _ := strconv.ParseInt(s, 10, 0)
+	return int(v)
+}
+
+func eventString(s string) string {
+	if s == "??" {
+		return ""
+	}
+	return s
+}
+
+type MouseAction int
+
+const (
+	MouseActionPress MouseAction = iota
+	MouseActionRelease
+	MouseActionMotion
+)
+
+var (
+	mouseActionName = []string{"press", "release", "motion"}
+)
+
+func (v MouseAction) String() string {
+	if v >= 0 && int(v) < len(mouseActionName) {
+		return mouseActionName[v]
+	}
+	return ""
+}
+
+// mouse button press, release and motion event
+type MouseEvent struct {
+	Widget     Widget
+	Action     MouseAction
+	Button     int // 0 for motion
+	PosX       int
+	PosY       int
+	GlobalPosX int
+	GlobalPosY int
+	State      EventState
+	Timestamp  int64
+}
+
+func (e *MouseEvent) KeyModifier() KeyModifier {
+	return e.State.KeyModifier()
+}
+
+// mouse wheel event, Delta is positive for scroll up (or left)
+type WheelEvent struct {
+	Widget     Widget
+	PosX       int
+	PosY       int
+	Delta      int
+	Horizontal bool
+	State      EventState
+}
+
+func (e *WheelEvent) KeyModifier() KeyModifier {
+	return e.State.KeyModifier()
+}
+
+// widget geometry changed event
+type ConfigureEvent struct {
+	Widget Widget
+	PosX   int
+	PosY   int
+	Width  int
+	Height int
+}
+
+// focus in and out event, Mode is NotifyNormal, NotifyGrab, NotifyUngrab or NotifyWhileGrabbed,
+// Detail is NotifyAncestor, NotifyVirtual, NotifyInferior, NotifyNonlinear, NotifyNonlinearVirtual, NotifyPointer ...
+type FocusEvent struct {
+	Widget Widget
+	In     bool
+	Mode   string
+	Detail string
+}
+
+// mouse enter and leave event, Focus is widget is focus window or it's descendant
+type CrossingEvent struct {
+	Widget     Widget
+	Enter      bool
+	PosX       int
+	PosY       int
+	GlobalPosX int
+	GlobalPosY int
+	Mode       string
+	Detail     string
+	Focus      bool
+	State      EventState
+}
+
+// virtual event, UserData is -data of event generate
+type VirtualEvent struct {
+	Widget    Widget
+	Name      string
+	UserData  string
+	Timestamp int64
+}
+
+func bindMouseAction(b *Binding, event string, action MouseAction, fn func(*MouseEvent)) error {
+	return b.addAction(event, "%W %b %x %y %X %Y %s %t", func(args []string) {
+		e := &MouseEvent{
+			Widget:     FindWidget(args[0]),
+			Action:     action,
+			PosX:       eventInt(args[2]),
+			PosY:       eventInt(args[3]),
+			GlobalPosX: eventInt(args[4]),
+			GlobalPosY: eventInt(args[5]),
+			State:      parserEventState(args[6]),
+			Timestamp:  int64(eventInt(args[7])),
+		}
+		if action != MouseActionMotion {
+			e.Button = eventInt(args[1])
+		}
+		fn(e)
+	})
+}
+
+// bind mouse button press, release and motion events for tag
+func BindMouse(tag string, fn func(e *MouseEvent)) (*Binding, error) {
+	if tag == "" || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	for _, v := range []struct {
+		event  string
+		action MouseAction
+	}{
+		{"<ButtonPress>", MouseActionPress},
+		{"<ButtonRelease>", MouseActionRelease},
+		{"<Motion>", MouseActionMotion},
+	} {
+		err := bindMouseAction(b, v.event, v.action, fn)
+		if err != nil {
+			b.Unbind()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// bind mouse wheel event for tag, x11 buttons 4-7 are reported as wheel
+func BindWheel(tag string, fn func(e *WheelEvent)) (*Binding, error) {
+	if tag == "" || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	bindDelta := func(event string, delta string, horizontal bool) error {
+		return b.addAction(event, "%W %x %y "+delta+" %s", func(args []string) {
+			e := &WheelEvent{
+				Widget:     FindWidget(args[0]),
+				PosX:       eventInt(args[1]),
+				PosY:       eventInt(args[2]),
+				Delta:      eventInt(args[3]),
+				Horizontal: horizontal,
+				State:      parserEventState(args[4]),
+			}
+			fn(e)
+		})
+	}
+	err := bindDelta("<MouseWheel>", "%D", false)
+	if err == nil && eventWindowingSystem() == "x11" {
+		err = bindDelta("<ButtonPress-4>", "120", false)
+		if err == nil {
+			err = bindDelta("<ButtonPress-5>", "-120", false)
+		}
+		// buttons 6 and 7 are supported by tk 8.6.8 or later
+		if err == nil && bindDelta("<ButtonPress-6>", "120", true) == nil {
+			bindDelta("<ButtonPress-7>", "-120", true)
+		}
+	}
+	if err != nil {
+		b.Unbind()
+		return nil, err
+	}
+	return b, nil
+}
+
+// bind configure event for tag
+func BindConfigure(tag string, fn func(e *ConfigureEvent)) (*Binding, error) {
+	if tag == "" || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	err := b.addAction("<Configure>", "%W %x %y %w %h", func(args []string) {
+		fn(&ConfigureEvent{
+			Widget: FindWidget(args[0]),
+			PosX:   eventInt(args[1]),
+			PosY:   eventInt(args[2]),
+			Width:  eventInt(args[3]),
+			Height: eventInt(args[4]),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// bind focus in and out events for tag
+func BindFocus(tag string, fn func(e *FocusEvent)) (*Binding, error) {
+	if tag == "" || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	for _, event := range []string{"<FocusIn>", "<FocusOut>"} {
+		in := event == "<FocusIn>"
+		err := b.addAction(event, "%W %m %d", func(args []string) {
+			fn(&FocusEvent{
+				Widget: FindWidget(args[0]),
+				In:     in,
+				Mode:   eventString(args[1]),
+				Detail: eventString(args[2]),
+			})
+		})
+		if err != nil {
+			b.Unbind()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// bind mouse enter and leave events for tag
+func BindCrossing(tag string, fn func(e *CrossingEvent)) (*Binding, error) {
+	if tag == "" || fn == nil {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	for _, event := range []string{"<Enter>", "<Leave>"} {
+		enter := event == "<Enter>"
+		err := b.addAction(event, "%W %x %y %X %Y %m %d %f %s", func(args []string) {
+			fn(&CrossingEvent{
+				Widget:     FindWidget(args[0]),
+				Enter:      enter,
+				PosX:       eventInt(args[1]),
+				PosY:       eventInt(args[2]),
+				GlobalPosX: eventInt(args[3]),
+				GlobalPosY: eventInt(args[4]),
+				Mode:       eventString(args[5]),
+				Detail:     eventString(args[6]),
+				Focus:      args[7] == "1",
+				State:      parserEventState(args[8]),
+			})
+		})
+		if err != nil {
+			b.Unbind()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// bind virtual event for tag, name is "<<Name>>" or "Name"
+func BindVirtual(tag string, name string, fn func(e *VirtualEvent)) (*Binding, error) {
+	if !IsVirtualEvent(name) {
+		name = "<<" + name + ">>"
+	}
+	if tag == "" || fn == nil || name == "<<>>" {
+		return nil, ErrInvalid
+	}
+	b := &Binding{tag: tag}
+	err := b.addAction(name, "%W %d %t", func(args []string) {
+		fn(&VirtualEvent{
+			Widget:    FindWidget(args[0]),
+			Name:      name,
+			UserData:  eventString(args[1]),
+			Timestamp: int64(eventInt(args[2])),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (w *BaseWidget) OnMouse(fn func(e *MouseEvent)) (*Binding, error) {
+	return BindMouse(w.id, fn)
+}
+
+func (w *BaseWidget) OnWheel(fn func(e *WheelEvent)) (*Binding, error) {
+	return BindWheel(w.id, fn)
+}
+
+func (w *BaseWidget) OnConfigure(fn func(e *ConfigureEvent)) (*Binding, error) {
+	return BindConfigure(w.id, fn)
+}
+
+func (w *BaseWidget) OnFocus(fn func(e *FocusEvent)) (*Binding, error) {
+	return BindFocus(w.id, fn)
+}
+
+func (w *BaseWidget) OnCrossing(fn func(e *CrossingEvent)) (*Binding, error) {
+	return BindCrossing(w.id, fn)
+}
+
+func (w *BaseWidget) OnVirtual(name string, fn func(e *VirtualEvent)) (*Binding, error) {
+	return BindVirtual(w.id, name, fn)
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 visualfc. All rights reserved.
+
+package tk
+
+import (
+	"testing"
+)
+
+func init() {
+	registerTest("TypedEvent", testTypedEvent)
+}
+
+func testTypedEvent(t *testing.T) {
+	w := NewFrame(nil)
+	defer w.Destroy()
+
+	var ve *VirtualEvent
+	b, err := w.OnVirtual("AtkTest", func(e *VirtualEvent) {
+		ve = e
+	})
+	if err != nil {
+		t.Fatal("OnVirtual", err)
+	}
+	SendEvent(w, "<<AtkTest>>", NativeEventAttr("data", "hello"))
+	if ve == nil || ve.Name != "<<AtkTest>>" || ve.UserData != "hello" || ve.Widget != w {
+		t.Fatal("OnVirtual", ve)
+	}
+	b.Unbind()
+
+	var me *MouseEvent
+	w.OnMouse(func(e *MouseEvent) {
+		me = e
+	})
+	SendEvent(w, "<ButtonPress-1>", NativeEventAttr("x", "5"), NativeEventAttr("y", "6"),
+		NativeEventAttr("state", "5"))
+	if me == nil || me.Action != MouseActionPress || me.Button != 1 || me.PosX != 5 || me.PosY != 6 {
+		t.Fatal("OnMouse", me)
+	}
+	if v := me.KeyModifier(); v != KeyModifierShift|KeyModifierControl {
+		t.Fatal("KeyModifier", KeyModifierShift|KeyModifierControl, v)
+	}
+	SendEvent(w, "<Motion>", NativeEventAttr("x", "7"), NativeEventAttr("y", "8"),
+		NativeEventAttr("state", "256"))
+	if me.Action != MouseActionMotion || me.Button != 0 || me.PosX != 7 || !me.State.IsButtonDown(1) {
+		t.Fatal("OnMouse", me)
+	}
+
+	var ce *ConfigureEvent
+	w.OnConfigure(func(e *ConfigureEvent) {
+		ce = e
+	})
+	SendEvent(w, "<Configure>", NativeEventAttr("width", "10"), NativeEventAttr("height", "20"))
+	if ce == nil || ce.Width != 10 || ce.Height != 20 {
+		t.Fatal("OnConfigure", ce)
+	}
+
+	var fe *FocusEvent
+	w.OnFocus(func(e *FocusEvent) {
+		fe = e
+	})
+	SendEvent(w, "<FocusOut>", NativeEventAttr("mode", "NotifyNormal"))
+	if fe == nil || fe.In || fe.Mode != "NotifyNormal" {
+		t.Fatal("OnFocus", fe)
+	}
+
+	var we *WheelEvent
+	w.OnWheel(func(e *WheelEvent) {
+		we = e
+	})
+	SendEvent(w, "<MouseWheel>", NativeEventAttr("delta", "-120"))
+	if we == nil || we.Delta != -120 {
+		t.Fatal("OnWheel", we)
+	}
+
+	if v := EventStateButton3.IsButtonDown(3); !v {
+		t.Fatal("IsButtonDown")
+	}
+}
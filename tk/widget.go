@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 )
 
 type Widget interface {
@@ -40,7 +41,8 @@ type Widget interface {
 }
 
 var (
-	globalWidgetMap = make(map[string]Widget)
+	globalWidgetMap  = make(map[string]Widget)
+	globalWidgetLock sync.RWMutex
 )
 
 func IsNilInterface(w Widget) bool {
@@ -54,15 +56,21 @@ func RegisterWidget(w Widget) {
 	if IsNilInterface(w) {
 		return
 	}
+	globalWidgetLock.Lock()
 	globalWidgetMap[w.Id()] = w
+	globalWidgetLock.Unlock()
 }
 
 func FindWidget(id string) Widget {
+	globalWidgetLock.RLock()
+	defer globalWidgetLock.RUnlock()
 	return globalWidgetMap[id]
 }
 
 func LookupWidget(id string) (w Widget, ok bool) {
+	globalWidgetLock.RLock()
 	w, ok = globalWidgetMap[id]
+	globalWidgetLock.RUnlock()
 	return
 }
 
@@ -80,13 +88,15 @@ func ParentOfWidget(w Widget) Widget {
 	} else if pos == 0 {
 		return rootWindow
 	}
-	return globalWidgetMap[id[:pos]]
+	return FindWidget(id[:pos])
 }
 
 func ChildrenOfWidget(w Widget) (list []Widget) {
 	if IsNilInterface(w) {
 		return nil
 	}
+	globalWidgetLock.RLock()
+	defer globalWidgetLock.RUnlock()
 	id := w.Id()
 	if id == "." {
 		for k, v := range globalWidgetMap {
@@ -115,6 +125,14 @@ func ChildrenOfWidget(w Widget) (list []Widget) {
 }
 
 func removeWidget(id string) {
+	removeWidgetHelper(id)
+	releaseWidgetActions(id)
+	releaseContextMenus(id)
+}
+
+func removeWidgetHelper(id string) {
+	globalWidgetLock.Lock()
+	defer globalWidgetLock.Unlock()
 	if id == "." {
 		globalWidgetMap = make(map[string]Widget)
 	} else {
@@ -136,7 +154,7 @@ func IsValidWidget(w Widget) bool {
 	if IsNilInterface(w) {
 		return false
 	}
-	_, ok := globalWidgetMap[w.Id()]
+	_, ok := LookupWidget(w.Id())
 	return ok
 }
 
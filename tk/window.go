@@ -341,6 +341,7 @@ func (w *Window) OnClose(fn func() (accept bool)) error {
 	if err != nil {
 		return err
 	}
+	addWidgetAction(w.id, actName)
 	return eval(fmt.Sprintf("wm protocol %v WM_DELETE_WINDOW %v", w.id, actName))
 }
 